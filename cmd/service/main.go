@@ -13,15 +13,20 @@ import (
 	"google.golang.org/grpc"
 
 	logger_lib "github.com/s21platform/logger-lib"
+	"github.com/s21platform/metrics-lib/pkg"
 
+	"github.com/s21platform/chat-service/internal/client/blobstore"
 	"github.com/s21platform/chat-service/internal/client/centrifugo"
+	"github.com/s21platform/chat-service/internal/client/realtime"
 	"github.com/s21platform/chat-service/internal/client/user"
 	"github.com/s21platform/chat-service/internal/config"
 	api "github.com/s21platform/chat-service/internal/generated"
 	"github.com/s21platform/chat-service/internal/infra"
+	"github.com/s21platform/chat-service/internal/outbox"
 	"github.com/s21platform/chat-service/internal/pkg/jwt"
 	"github.com/s21platform/chat-service/internal/pkg/tx"
 	"github.com/s21platform/chat-service/internal/pkg/validator"
+	"github.com/s21platform/chat-service/internal/presence"
 	db "github.com/s21platform/chat-service/internal/repository/postgres"
 	"github.com/s21platform/chat-service/internal/rest"
 	"github.com/s21platform/chat-service/internal/service"
@@ -37,13 +42,26 @@ func main() {
 
 	userClient := user.New(cfg)
 
+	publisher := realtime.New(cfg)
+	defer publisher.Close()
+
 	centrifugeClient := centrifugo.New(cfg)
 	defer centrifugeClient.Close()
 
+	var blobStore rest.BlobStore
+	switch cfg.Storage.Backend {
+	case "s3":
+		s3Store := blobstore.NewS3(cfg)
+		defer s3Store.Close()
+		blobStore = s3Store
+	default:
+		blobStore = blobstore.NewLocal(cfg)
+	}
+
 	vldtr := validator.New()
 	jwtGenerator := jwt.New(cfg.Centrifuge.JWTSecret)
 
-	chatService := service.New()
+	chatService := service.New(dbRepo)
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			infra.AuthInterceptorGRPC,
@@ -53,9 +71,18 @@ func main() {
 	)
 	chat.RegisterChatServiceServer(grpcServer, chatService)
 
-	handler := rest.New(dbRepo, userClient, centrifugeClient, vldtr, jwtGenerator)
+	// без Centrifugo presence пуст: онлайн-статусы и присутствие в стриме деградируют, а не отвечают ошибкой
+	var presenceClient rest.PresenceClient = centrifugeClient
+	if cfg.Realtime.Backend == realtime.MemoryBackend {
+		presenceClient = realtime.NewNoPresence()
+	}
+
+	handler := rest.New(dbRepo, userClient, publisher, presenceClient, vldtr, jwtGenerator, blobStore, cfg)
 	router := chi.NewRouter()
 
+	router.Use(func(next http.Handler) http.Handler {
+		return infra.CentrifugoProxyAuthHTTP(next, cfg.Centrifuge.ProxyKey)
+	})
 	router.Use(func(next http.Handler) http.Handler {
 		return infra.AuthInterceptorHTTP(next)
 	})
@@ -81,7 +108,28 @@ func main() {
 	grpcListener := m.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
 	httpListener := m.Match(cmux.HTTP1Fast())
 
-	g, _ := errgroup.WithContext(context.Background())
+	g, gCtx := errgroup.WithContext(context.Background())
+
+	// backend memory отбрасывает публикации, поэтому отдельный воркер ему не нужен:
+	// relay здесь лишь освобождает outbox и чистит журнал событий
+	if cfg.Realtime.Backend == realtime.MemoryBackend {
+		metrics, err := pkg.NewMetrics(cfg.Metrics.Host, cfg.Metrics.Port, cfg.Service.Name, cfg.Platform.Env)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to connect graphite: %v", err))
+		}
+
+		relayCtx := context.WithValue(gCtx, config.KeyLogger, logger)
+		relayCtx = context.WithValue(relayCtx, config.KeyMetrics, metrics)
+		relay := outbox.New(dbRepo, publisher, cfg)
+		go relay.Run(relayCtx)
+	}
+
+	// presence есть только у Centrifugo; без неё last_online обновляют лишь connect и refresh proxy
+	if cfg.Realtime.Backend != realtime.MemoryBackend {
+		sweeperCtx := context.WithValue(gCtx, config.KeyLogger, logger)
+		sweeper := presence.New(dbRepo, centrifugeClient, cfg)
+		go sweeper.Run(sweeperCtx)
+	}
 
 	g.Go(func() error {
 		if err := grpcServer.Serve(grpcListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	logger_lib "github.com/s21platform/logger-lib"
+	"github.com/s21platform/metrics-lib/pkg"
+
+	"github.com/s21platform/chat-service/internal/client/realtime"
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/outbox"
+	"github.com/s21platform/chat-service/internal/repository/postgres"
+)
+
+func main() {
+	cfg := config.MustLoad()
+	logger := logger_lib.New(cfg.Logger.Host, cfg.Logger.Port, cfg.Service.Name, cfg.Platform.Env)
+
+	// backend memory отбрасывает публикации, и relay для него запускает сам сервис
+	if cfg.Realtime.Backend == realtime.MemoryBackend {
+		logger.Info("outbox worker is disabled for the memory realtime backend")
+		return
+	}
+
+	dbRepo := postgres.New(cfg)
+	defer dbRepo.Close()
+
+	publisher := realtime.New(cfg)
+	defer publisher.Close()
+
+	metrics, err := pkg.NewMetrics(cfg.Metrics.Host, cfg.Metrics.Port, cfg.Service.Name, cfg.Platform.Env)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to connect graphite: %v", err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx = context.WithValue(ctx, config.KeyMetrics, metrics)
+	ctx = context.WithValue(ctx, config.KeyLogger, logger)
+
+	relay := outbox.New(dbRepo, publisher, cfg)
+	relay.Run(ctx)
+}
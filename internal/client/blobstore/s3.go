@@ -0,0 +1,147 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/s21platform/chat-service/internal/config"
+)
+
+type S3 struct {
+	endpoint   *url.URL
+	bucket     string
+	region     string
+	accessKey  string
+	secretKey  string
+	pathStyle  bool
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func NewS3(cfg *config.Config) *S3 {
+	endpoint, err := url.Parse(cfg.Storage.S3Endpoint)
+	if err != nil || endpoint.Host == "" {
+		log.Fatalf("invalid S3 endpoint: %s", cfg.Storage.S3Endpoint)
+	}
+
+	return &S3{
+		endpoint:  endpoint,
+		bucket:    cfg.Storage.S3Bucket,
+		region:    cfg.Storage.S3Region,
+		accessKey: cfg.Storage.S3AccessKey,
+		secretKey: cfg.Storage.S3SecretKey,
+		pathStyle: cfg.Storage.S3PathStyle,
+		httpClient: &http.Client{
+			Timeout: cfg.Storage.Timeout,
+		},
+		now: time.Now,
+	}
+}
+
+func (s *S3) Close() {
+	s.httpClient.CloseIdleConnections()
+}
+
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, s.now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // .
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	s.sign(req, s.now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close() //nolint:errcheck // .
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrObjectNotFound
+		}
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	return resp.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	s.sign(req, s.now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // .
+
+	// S3 отвечает 204 и на удаление отсутствующего объекта
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
+
+func (s *S3) PresignPut(_ context.Context, key, contentType string, size int64, expires time.Duration) (string, error) {
+	headers := map[string]string{
+		"content-type":   contentType,
+		"content-length": strconv.FormatInt(size, 10),
+	}
+
+	return s.presign(http.MethodPut, key, headers, expires, s.now()), nil
+}
+
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	path := "/" + key
+	if s.pathStyle {
+		path = "/" + s.bucket + path
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+
+	u.Path = strings.TrimSuffix(s.endpoint.Path, "/") + path
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = ""
+
+	return &u
+}
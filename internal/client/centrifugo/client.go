@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/s21platform/chat-service/internal/config"
@@ -12,9 +14,15 @@ import (
 )
 
 const (
-	publishMethod = "publish"
+	publishMethod       = "publish"
+	broadcastMethod     = "broadcast"
+	presenceMethod      = "presence"
+	presenceStatsMethod = "presence_stats"
+	unsubscribeMethod   = "unsubscribe"
 )
 
+var errMissingReply = errors.New("centrifugo returned no reply")
+
 type Client struct {
 	baseURL    string
 	apiKey     string
@@ -35,13 +43,176 @@ func (c *Client) Close() {
 	c.httpClient.CloseIdleConnections()
 }
 
-func (c *Client) Publish(ctx context.Context, channel string, msg model.Message) error {
+func (c *Client) Publish(ctx context.Context, channel string, data interface{}) error {
+	return c.call(ctx, publishMethod, model.CentrifugoEventParams{
+		Channel: channel,
+		Data:    data,
+	}, nil)
+}
+
+// Broadcast публикует одни и те же данные во все каналы одним вызовом;
+// каналы, в которые доставить не удалось, перечислены в *model.BatchPublishError
+func (c *Client) Broadcast(ctx context.Context, channels []string, data interface{}) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	var result model.CentrifugoBroadcastResult
+	err := c.call(ctx, broadcastMethod, model.CentrifugoBroadcastParams{
+		Channels: channels,
+		Data:     data,
+	}, &result)
+	if err != nil {
+		return err
+	}
+
+	var failed []model.ChannelPublishError
+	for i, channel := range channels {
+		if i >= len(result.Responses) {
+			failed = append(failed, model.ChannelPublishError{Index: i, Channel: channel, Err: errMissingReply})
+			continue
+		}
+
+		if result.Responses[i].Error != nil {
+			failed = append(failed, model.ChannelPublishError{Index: i, Channel: channel, Err: replyError(result.Responses[i].Error)})
+		}
+	}
+
+	return model.JoinPublishErrors(failed)
+}
+
+// PublishBatch отправляет публикации одним HTTP-запросом: Centrifugo выполняет команды,
+// разделённые переводом строки, по порядку и отвечает на каждую отдельной строкой
+func (c *Client) PublishBatch(ctx context.Context, publications []model.ChannelPublication) error {
+	if len(publications) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, publication := range publications {
+		err := encoder.Encode(model.CentrifugoEvent{
+			Method: publishMethod,
+			Params: model.CentrifugoEventParams{
+				Channel: publication.Channel,
+				Data:    publication.Data,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	var failed []model.ChannelPublishError
+	err := c.post(ctx, body.Bytes(), func(decoder *json.Decoder) error {
+		for i, publication := range publications {
+			var response model.CentrifugoResponse
+			if err := decoder.Decode(&response); err != nil {
+				if errors.Is(err, io.EOF) {
+					failed = append(failed, model.ChannelPublishError{Index: i, Channel: publication.Channel, Err: errMissingReply})
+					continue
+				}
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			if response.Error != nil {
+				failed = append(failed, model.ChannelPublishError{Index: i, Channel: publication.Channel, Err: replyError(response.Error)})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return model.JoinPublishErrors(failed)
+}
+
+// Presence возвращает подключённых к каналу клиентов по их client ID
+func (c *Client) Presence(ctx context.Context, channel string) (map[string]model.CentrifugoClientInfo, error) {
+	var result model.CentrifugoPresenceResult
+	err := c.call(ctx, presenceMethod, model.CentrifugoChannelParams{Channel: channel}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Presence, nil
+}
+
+func (c *Client) PresenceStats(ctx context.Context, channel string) (*model.PresenceStats, error) {
+	var result model.PresenceStats
+	err := c.call(ctx, presenceStatsMethod, model.CentrifugoChannelParams{Channel: channel}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// PresenceStatsBatch запрашивает presence_stats всех каналов одним HTTP-запросом. Ответы идут в порядке
+// каналов; для канала, по которому Centrifugo вернул ошибку, ответ nil, а ошибки объединены в err
+func (c *Client) PresenceStatsBatch(ctx context.Context, channels []string) ([]*model.PresenceStats, error) {
+	if len(channels) == 0 {
+		return nil, nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, channel := range channels {
+		err := encoder.Encode(model.CentrifugoEvent{
+			Method: presenceStatsMethod,
+			Params: model.CentrifugoChannelParams{Channel: channel},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	stats := make([]*model.PresenceStats, len(channels))
+	var failed []error
+	err := c.post(ctx, body.Bytes(), func(decoder *json.Decoder) error {
+		for i, channel := range channels {
+			var response model.CentrifugoResponse
+			if err := decoder.Decode(&response); err != nil {
+				if errors.Is(err, io.EOF) {
+					failed = append(failed, fmt.Errorf("channel %s: %w", channel, errMissingReply))
+					continue
+				}
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			if response.Error != nil {
+				failed = append(failed, fmt.Errorf("channel %s: %w", channel, replyError(response.Error)))
+				continue
+			}
+
+			var result model.PresenceStats
+			if err := json.Unmarshal(response.Result, &result); err != nil {
+				failed = append(failed, fmt.Errorf("channel %s: failed to decode %s result: %w", channel, presenceStatsMethod, err))
+				continue
+			}
+			stats[i] = &result
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, errors.Join(failed...)
+}
+
+// Unsubscribe закрывает подписки всех соединений пользователя на канал
+func (c *Client) Unsubscribe(ctx context.Context, user, channel string) error {
+	return c.call(ctx, unsubscribeMethod, model.CentrifugoUnsubscribeParams{User: user, Channel: channel}, nil)
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
 	payload := model.CentrifugoEvent{
-		Method: publishMethod,
-		Params: model.CentrifugoEventParams{
-			Channel: channel,
-			Data:    msg,
-		},
+		Method: method,
+		Params: params,
 	}
 
 	jsonData, err := json.Marshal(payload)
@@ -49,7 +220,28 @@ func (c *Client) Publish(ctx context.Context, channel string, msg model.Message)
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api", bytes.NewBuffer(jsonData))
+	return c.post(ctx, jsonData, func(decoder *json.Decoder) error {
+		var response model.CentrifugoResponse
+		if err := decoder.Decode(&response); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		if response.Error != nil {
+			return replyError(response.Error)
+		}
+
+		if result != nil && len(response.Result) > 0 {
+			if err := json.Unmarshal(response.Result, result); err != nil {
+				return fmt.Errorf("failed to decode %s result: %w", method, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (c *Client) post(ctx context.Context, body []byte, decode func(decoder *json.Decoder) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api", bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -67,14 +259,9 @@ func (c *Client) Publish(ctx context.Context, channel string, msg model.Message)
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	var response map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	if errorData, exists := response["error"]; exists && errorData != nil {
-		return fmt.Errorf("centrifugo error: %v", errorData)
-	}
+	return decode(json.NewDecoder(resp.Body))
+}
 
-	return nil
+func replyError(err *model.CentrifugoError) error {
+	return fmt.Errorf("centrifugo error: %d: %s", err.Code, err.Message)
 }
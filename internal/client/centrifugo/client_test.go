@@ -0,0 +1,205 @@
+package centrifugo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return New(&config.Config{
+		Centrifuge: config.Centrifuge{
+			BaseURL: server.URL,
+			APIKey:  "key",
+			Timeout: time.Second,
+		},
+	})
+}
+
+func TestClient_Presence(t *testing.T) {
+	t.Parallel()
+
+	t.Run("presence", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "apikey key", r.Header.Get("Authorization"))
+
+			var event struct {
+				Method string                        `json:"method"`
+				Params model.CentrifugoChannelParams `json:"params"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+			assert.Equal(t, presenceMethod, event.Method)
+			assert.Equal(t, "stream", event.Params.Channel)
+
+			_, _ = w.Write([]byte(`{"result":{"presence":{"c1":{"client":"c1","user":"u1"}}}}`))
+		})
+
+		presence, err := client.Presence(context.Background(), "stream")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]model.CentrifugoClientInfo{"c1": {Client: "c1", User: "u1"}}, presence)
+	})
+
+	t.Run("presence_stats", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"result":{"num_clients":3,"num_users":2}}`))
+		})
+
+		stats, err := client.PresenceStats(context.Background(), "stream")
+		require.NoError(t, err)
+		assert.Equal(t, &model.PresenceStats{NumClients: 3, NumUsers: 2}, stats)
+	})
+
+	t.Run("presence_stats_batch", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			decoder := json.NewDecoder(r.Body)
+
+			var channels []string
+			for decoder.More() {
+				var event struct {
+					Method string                        `json:"method"`
+					Params model.CentrifugoChannelParams `json:"params"`
+				}
+				require.NoError(t, decoder.Decode(&event))
+				assert.Equal(t, presenceStatsMethod, event.Method)
+				channels = append(channels, event.Params.Channel)
+			}
+			assert.Equal(t, []string{"a", "b", "c"}, channels)
+
+			_, _ = w.Write([]byte("{\"result\":{\"num_clients\":2,\"num_users\":1}}\n{\"error\":{\"code\":108,\"message\":\"not available\"}}\n"))
+		})
+
+		stats, err := client.PresenceStatsBatch(context.Background(), []string{"a", "b", "c"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not available")
+		assert.Equal(t, []*model.PresenceStats{{NumClients: 2, NumUsers: 1}, nil, nil}, stats)
+	})
+
+	t.Run("centrifugo_error", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"error":{"code":108,"message":"not available"}}`))
+		})
+
+		_, err := client.PresenceStats(context.Background(), "stream")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not available")
+	})
+}
+
+func TestClient_Broadcast(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var event struct {
+			Method string                          `json:"method"`
+			Params model.CentrifugoBroadcastParams `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		assert.Equal(t, broadcastMethod, event.Method)
+		assert.Equal(t, []string{"a", "b"}, event.Params.Channels)
+
+		_, _ = w.Write([]byte(`{"result":{"responses":[{"result":{}},{"error":{"code":102,"message":"unknown channel"}}]}}`))
+	})
+
+	err := client.Broadcast(context.Background(), []string{"a", "b"}, map[string]string{"type": "stream.updated"})
+
+	var batchErr *model.BatchPublishError
+	require.ErrorAs(t, err, &batchErr)
+	require.Len(t, batchErr.Failed, 1)
+	assert.Equal(t, "b", batchErr.Failed[0].Channel)
+	assert.Contains(t, batchErr.Failed[0].Err.Error(), "unknown channel")
+}
+
+func TestClient_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var event struct {
+			Method string                            `json:"method"`
+			Params model.CentrifugoUnsubscribeParams `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		assert.Equal(t, unsubscribeMethod, event.Method)
+		assert.Equal(t, model.CentrifugoUnsubscribeParams{User: "u1", Channel: "stream"}, event.Params)
+
+		_, _ = w.Write([]byte(`{"result":{}}`))
+	})
+
+	require.NoError(t, client.Unsubscribe(context.Background(), "u1", "stream"))
+}
+
+func TestClient_PublishBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("partial_failure", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			decoder := json.NewDecoder(r.Body)
+
+			var channels []string
+			for decoder.More() {
+				var event struct {
+					Method string                      `json:"method"`
+					Params model.CentrifugoEventParams `json:"params"`
+				}
+				require.NoError(t, decoder.Decode(&event))
+				assert.Equal(t, publishMethod, event.Method)
+				channels = append(channels, event.Params.Channel)
+			}
+			assert.Equal(t, []string{"a", "b", "c"}, channels)
+
+			_, _ = w.Write([]byte("{\"result\":{}}\n{\"error\":{\"code\":102,\"message\":\"unknown channel\"}}\n{\"result\":{}}\n"))
+		})
+
+		err := client.PublishBatch(context.Background(), []model.ChannelPublication{
+			{Channel: "a", Data: 1},
+			{Channel: "b", Data: 2},
+			{Channel: "c", Data: 3},
+		})
+
+		var batchErr *model.BatchPublishError
+		require.ErrorAs(t, err, &batchErr)
+		require.Len(t, batchErr.Failed, 1)
+		assert.Equal(t, 1, batchErr.Failed[0].Index)
+		assert.Equal(t, "b", batchErr.Failed[0].Channel)
+	})
+
+	t.Run("missing_replies", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("{\"result\":{}}\n"))
+		})
+
+		err := client.PublishBatch(context.Background(), []model.ChannelPublication{
+			{Channel: "a", Data: 1},
+			{Channel: "b", Data: 2},
+		})
+
+		var batchErr *model.BatchPublishError
+		require.ErrorAs(t, err, &batchErr)
+		require.Len(t, batchErr.Failed, 1)
+		assert.Equal(t, "b", batchErr.Failed[0].Channel)
+	})
+
+	t.Run("server_error", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		err := client.PublishBatch(context.Background(), []model.ChannelPublication{{Channel: "a", Data: 1}})
+		require.Error(t, err)
+
+		var batchErr *model.BatchPublishError
+		assert.False(t, errors.As(err, &batchErr))
+	})
+}
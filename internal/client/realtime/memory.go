@@ -0,0 +1,54 @@
+package realtime
+
+import (
+	"context"
+
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+// Discard — publisher backend'а memory: брокера нет, и публикации никуда не доставляются.
+// Клиенты получают события только через SSE, который читает журнал событий в Postgres
+type Discard struct{}
+
+func NewDiscard() *Discard {
+	return &Discard{}
+}
+
+func (d *Discard) Publish(_ context.Context, _ string, _ interface{}) error {
+	return nil
+}
+
+func (d *Discard) Broadcast(_ context.Context, _ []string, _ interface{}) error {
+	return nil
+}
+
+func (d *Discard) PublishBatch(_ context.Context, _ []model.ChannelPublication) error {
+	return nil
+}
+
+func (d *Discard) Close() {}
+
+// NoPresence заменяет server API Centrifugo для backend'а memory: к Centrifugo никто не подключён,
+// поэтому presence пуст, а закрывать подписки не нужно
+type NoPresence struct{}
+
+func NewNoPresence() *NoPresence {
+	return &NoPresence{}
+}
+
+func (p *NoPresence) Presence(_ context.Context, _ string) (map[string]model.CentrifugoClientInfo, error) {
+	return map[string]model.CentrifugoClientInfo{}, nil
+}
+
+func (p *NoPresence) PresenceStatsBatch(_ context.Context, channels []string) ([]*model.PresenceStats, error) {
+	stats := make([]*model.PresenceStats, len(channels))
+	for i := range stats {
+		stats[i] = &model.PresenceStats{}
+	}
+
+	return stats, nil
+}
+
+func (p *NoPresence) Unsubscribe(_ context.Context, _, _ string) error {
+	return nil
+}
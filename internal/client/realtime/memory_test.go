@@ -0,0 +1,27 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+func TestNoPresence(t *testing.T) {
+	t.Parallel()
+
+	presence := NewNoPresence()
+
+	clients, err := presence.Presence(context.Background(), "stream")
+	require.NoError(t, err)
+	assert.Empty(t, clients)
+
+	stats, err := presence.PresenceStatsBatch(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.PresenceStats{{}, {}}, stats)
+
+	assert.NoError(t, presence.Unsubscribe(context.Background(), "user", "stream"))
+}
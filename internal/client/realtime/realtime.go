@@ -0,0 +1,37 @@
+package realtime
+
+import (
+	"context"
+	"log"
+
+	"github.com/s21platform/chat-service/internal/client/centrifugo"
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+const (
+	CentrifugoBackend = "centrifugo"
+	MemoryBackend     = "memory"
+	RedisBackend      = "redis"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, channel string, data interface{}) error
+	Broadcast(ctx context.Context, channels []string, data interface{}) error
+	PublishBatch(ctx context.Context, publications []model.ChannelPublication) error
+	Close()
+}
+
+func New(cfg *config.Config) Publisher {
+	switch cfg.Realtime.Backend {
+	case CentrifugoBackend, "":
+		return centrifugo.New(cfg)
+	case MemoryBackend:
+		return NewDiscard()
+	case RedisBackend:
+		return NewRedis(cfg)
+	default:
+		log.Fatalf("unknown realtime backend: %s", cfg.Realtime.Backend)
+		return nil
+	}
+}
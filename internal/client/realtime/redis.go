@@ -0,0 +1,150 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+const (
+	RedisPubSubMode = "pubsub"
+	RedisStreamMode = "stream"
+)
+
+// Redis публикует события через PUBLISH или в Redis Streams через XADD
+type Redis struct {
+	client    *redis.Client
+	mode      string
+	keyPrefix string
+	maxLen    int64
+}
+
+type pipelinedPublication struct {
+	index   int
+	channel string
+	payload []byte
+}
+
+func NewRedis(cfg *config.Config) *Redis {
+	mode := cfg.Realtime.RedisMode
+	if mode != RedisPubSubMode && mode != RedisStreamMode {
+		log.Fatalf("unknown redis realtime mode: %s", mode)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         cfg.Realtime.RedisAddr,
+		Password:     cfg.Realtime.RedisPassword,
+		DB:           cfg.Realtime.RedisDB,
+		PoolSize:     cfg.Realtime.RedisPoolSize,
+		DialTimeout:  cfg.Realtime.Timeout,
+		ReadTimeout:  cfg.Realtime.Timeout,
+		WriteTimeout: cfg.Realtime.Timeout,
+	})
+
+	return newRedis(client, mode, cfg.Realtime.RedisKeyPrefix, cfg.Realtime.RedisStreamMaxLen)
+}
+
+func newRedis(client *redis.Client, mode, keyPrefix string, maxLen int64) *Redis {
+	return &Redis{
+		client:    client,
+		mode:      mode,
+		keyPrefix: keyPrefix,
+		maxLen:    maxLen,
+	}
+}
+
+func (r *Redis) Close() {
+	_ = r.client.Close()
+}
+
+func (r *Redis) Publish(ctx context.Context, channel string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal publication: %w", err)
+	}
+
+	if err := r.command(ctx, r.client, channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish to redis: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Redis) Broadcast(ctx context.Context, channels []string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal publication: %w", err)
+	}
+
+	batch := make([]pipelinedPublication, 0, len(channels))
+	for i, channel := range channels {
+		batch = append(batch, pipelinedPublication{index: i, channel: channel, payload: payload})
+	}
+
+	return r.pipeline(ctx, batch, nil)
+}
+
+// PublishBatch отправляет публикации одним пайплайном
+func (r *Redis) PublishBatch(ctx context.Context, publications []model.ChannelPublication) error {
+	batch := make([]pipelinedPublication, 0, len(publications))
+	var failed []model.ChannelPublishError
+	for i, publication := range publications {
+		payload, err := json.Marshal(publication.Data)
+		if err != nil {
+			failed = append(failed, model.ChannelPublishError{Index: i, Channel: publication.Channel, Err: fmt.Errorf("failed to marshal publication: %w", err)})
+			continue
+		}
+
+		batch = append(batch, pipelinedPublication{index: i, channel: publication.Channel, payload: payload})
+	}
+
+	return r.pipeline(ctx, batch, failed)
+}
+
+func (r *Redis) pipeline(ctx context.Context, batch []pipelinedPublication, failed []model.ChannelPublishError) error {
+	if len(batch) == 0 {
+		return model.JoinPublishErrors(failed)
+	}
+
+	pipe := r.client.Pipeline()
+	commands := make([]redis.Cmder, 0, len(batch))
+	for _, publication := range batch {
+		commands = append(commands, r.command(ctx, pipe, publication.channel, publication.payload))
+	}
+
+	// ошибка Redis в ответе на отдельную команду не мешает остальным, любая другая означает, что пайплайн не выполнен
+	var redisErr redis.Error
+	if _, err := pipe.Exec(ctx); err != nil && !errors.As(err, &redisErr) {
+		return fmt.Errorf("failed to publish to redis: %w", err)
+	}
+
+	for i, command := range commands {
+		if err := command.Err(); err != nil {
+			failed = append(failed, model.ChannelPublishError{Index: batch[i].index, Channel: batch[i].channel, Err: err})
+		}
+	}
+
+	return model.JoinPublishErrors(failed)
+}
+
+func (r *Redis) command(ctx context.Context, client redis.Cmdable, channel string, payload []byte) redis.Cmder {
+	key := r.keyPrefix + channel
+
+	if r.mode == RedisStreamMode {
+		return client.XAdd(ctx, &redis.XAddArgs{
+			Stream: key,
+			MaxLen: r.maxLen,
+			Approx: true,
+			Values: []interface{}{"data", string(payload)},
+		})
+	}
+
+	return client.Publish(ctx, key, string(payload))
+}
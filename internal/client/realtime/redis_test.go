@@ -0,0 +1,112 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+func newTestRedis(t *testing.T, mode string, maxLen int64) (*Redis, *redis.Client, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	r := newRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}), mode, "chat:", maxLen)
+	t.Cleanup(r.Close)
+
+	return r, client, server
+}
+
+func TestRedis_Publish(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pubsub", func(t *testing.T) {
+		r, client, _ := newTestRedis(t, RedisPubSubMode, 0)
+		ctx := context.Background()
+
+		sub := client.Subscribe(ctx, "chat:stream")
+		defer sub.Close() //nolint:errcheck // .
+		_, err := sub.Receive(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, r.Publish(ctx, "stream", map[string]int{"version": 1}))
+
+		select {
+		case message := <-sub.Channel():
+			assert.Equal(t, `{"version":1}`, message.Payload)
+		case <-time.After(time.Second):
+			t.Fatal("publication was not received")
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		r, client, _ := newTestRedis(t, RedisStreamMode, 100)
+		ctx := context.Background()
+
+		require.NoError(t, r.Publish(ctx, "stream", "hello"))
+
+		entries, err := client.XRange(ctx, "chat:stream", "-", "+").Result()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, map[string]interface{}{"data": `"hello"`}, entries[0].Values)
+	})
+
+	t.Run("redis_error", func(t *testing.T) {
+		r, _, server := newTestRedis(t, RedisStreamMode, 0)
+		require.NoError(t, server.Set("chat:stream", "not a stream"))
+
+		err := r.Publish(context.Background(), "stream", "hello")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "WRONGTYPE")
+	})
+}
+
+func TestRedis_PublishBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("partial_failure", func(t *testing.T) {
+		r, client, server := newTestRedis(t, RedisStreamMode, 0)
+		require.NoError(t, server.Set("chat:b", "not a stream"))
+		ctx := context.Background()
+
+		err := r.PublishBatch(ctx, []model.ChannelPublication{
+			{Channel: "a", Data: 1},
+			{Channel: "b", Data: 2},
+			{Channel: "c", Data: 3},
+		})
+
+		var batchErr *model.BatchPublishError
+		require.ErrorAs(t, err, &batchErr)
+		require.Len(t, batchErr.Failed, 1)
+		assert.Equal(t, 1, batchErr.Failed[0].Index)
+		assert.Equal(t, "b", batchErr.Failed[0].Channel)
+
+		for _, key := range []string{"chat:a", "chat:c"} {
+			entries, err := client.XRange(ctx, key, "-", "+").Result()
+			require.NoError(t, err)
+			assert.Len(t, entries, 1)
+		}
+	})
+
+	t.Run("connection_error", func(t *testing.T) {
+		r, _, server := newTestRedis(t, RedisPubSubMode, 0)
+		server.Close()
+
+		err := r.PublishBatch(context.Background(), []model.ChannelPublication{
+			{Channel: "a", Data: 1},
+		})
+
+		var batchErr *model.BatchPublishError
+		require.Error(t, err)
+		assert.False(t, errors.As(err, &batchErr))
+	})
+}
@@ -16,6 +16,12 @@ type Config struct {
 	UserService UserService
 	Kafka       Kafka
 	Centrifuge  Centrifuge
+	Messages    Messages
+	Storage     Storage
+	Outbox      Outbox
+	Realtime    Realtime
+	SSE         SSE
+	Typing      Typing
 }
 
 type Service struct {
@@ -62,6 +68,70 @@ type Centrifuge struct {
 	APIKey    string        `env:"CENTRIFUGE_API_KEY"`
 	Timeout   time.Duration `env:"CENTRIFUGE_TIMEOUT" env-default:"10s"`
 	JWTSecret string        `env:"CENTRIFUGE_JWT_SECRET"`
+	// ProxyKey передаётся Centrifugo в заголовке X-Centrifugo-Proxy-Key при вызове proxy-хуков;
+	// пока ключ не задан, proxy-хуки отклоняются. Connect-хук доверяет X-User-ID, поэтому /centrifugo/*
+	// не должны быть доступны никому, кроме Centrifugo
+	ProxyKey string        `env:"CENTRIFUGE_PROXY_KEY"`
+	ProxyTTL time.Duration `env:"CENTRIFUGE_PROXY_TTL" env-default:"10m"`
+	// PresenceSweepInterval — как часто last_online подключённых пользователей сверяется с presence
+	PresenceSweepInterval time.Duration `env:"CENTRIFUGE_PRESENCE_SWEEP_INTERVAL" env-default:"1m"`
+}
+
+type Messages struct {
+	EditWindow time.Duration `env:"CHAT_MESSAGE_EDIT_WINDOW" env-default:"24h"`
+}
+
+type Storage struct {
+	Backend          string        `env:"CHAT_STORAGE_BACKEND" env-default:"local"`
+	LocalPath        string        `env:"CHAT_STORAGE_LOCAL_PATH" env-default:"./attachments"`
+	S3Endpoint       string        `env:"CHAT_STORAGE_S3_ENDPOINT"`
+	S3Region         string        `env:"CHAT_STORAGE_S3_REGION" env-default:"us-east-1"`
+	S3Bucket         string        `env:"CHAT_STORAGE_S3_BUCKET"`
+	S3AccessKey      string        `env:"CHAT_STORAGE_S3_ACCESS_KEY"`
+	S3SecretKey      string        `env:"CHAT_STORAGE_S3_SECRET_KEY"`
+	S3PathStyle      bool          `env:"CHAT_STORAGE_S3_PATH_STYLE" env-default:"true"`
+	Timeout          time.Duration `env:"CHAT_STORAGE_TIMEOUT" env-default:"30s"`
+	PresignTTL       time.Duration `env:"CHAT_STORAGE_PRESIGN_TTL" env-default:"15m"`
+	MaxUploadSize    int64         `env:"CHAT_STORAGE_MAX_UPLOAD_SIZE" env-default:"20971520"`
+	AllowedMimeTypes []string      `env:"CHAT_STORAGE_ALLOWED_MIME_TYPES" env-default:"image/*,video/*,audio/*,application/pdf,application/zip,text/plain"`
+	ThumbnailSize    int           `env:"CHAT_STORAGE_THUMBNAIL_SIZE" env-default:"320"`
+}
+
+type Outbox struct {
+	PollInterval time.Duration `env:"CHAT_OUTBOX_POLL_INTERVAL" env-default:"500ms"`
+	BatchSize    int           `env:"CHAT_OUTBOX_BATCH_SIZE" env-default:"100"`
+	BaseBackoff  time.Duration `env:"CHAT_OUTBOX_BASE_BACKOFF" env-default:"1s"`
+	MaxBackoff   time.Duration `env:"CHAT_OUTBOX_MAX_BACKOFF" env-default:"5m"`
+	MaxAttempts  int           `env:"CHAT_OUTBOX_MAX_ATTEMPTS" env-default:"20"`
+	// EventRetention — сколько хранится журнал событий, из которого SSE продолжает поток по Last-Event-ID
+	EventRetention time.Duration `env:"CHAT_OUTBOX_EVENT_RETENTION" env-default:"24h"`
+}
+
+// Realtime выбирает, куда публикуются события: centrifugo, redis или memory — без брокера,
+// публикации отбрасываются, а клиентам доступен только SSE по журналу событий
+type Realtime struct {
+	Backend           string        `env:"CHAT_REALTIME_BACKEND" env-default:"centrifugo"`
+	RedisAddr         string        `env:"CHAT_REALTIME_REDIS_ADDR" env-default:"localhost:6379"`
+	RedisPassword     string        `env:"CHAT_REALTIME_REDIS_PASSWORD"`
+	RedisDB           int           `env:"CHAT_REALTIME_REDIS_DB"`
+	RedisPoolSize     int           `env:"CHAT_REALTIME_REDIS_POOL_SIZE" env-default:"10"`
+	RedisMode         string        `env:"CHAT_REALTIME_REDIS_MODE" env-default:"pubsub"`
+	RedisKeyPrefix    string        `env:"CHAT_REALTIME_REDIS_KEY_PREFIX" env-default:"chat:"`
+	RedisStreamMaxLen int64         `env:"CHAT_REALTIME_REDIS_STREAM_MAXLEN" env-default:"10000"`
+	Timeout           time.Duration `env:"CHAT_REALTIME_TIMEOUT" env-default:"5s"`
+}
+
+// SSE — поток событий для клиентов без Centrifugo
+type SSE struct {
+	PollInterval      time.Duration `env:"CHAT_SSE_POLL_INTERVAL" env-default:"1s"`
+	HeartbeatInterval time.Duration `env:"CHAT_SSE_HEARTBEAT_INTERVAL" env-default:"15s"`
+	BatchSize         int           `env:"CHAT_SSE_BATCH_SIZE" env-default:"100"`
+}
+
+// Typing — Interval ограничивает частоту событий набора текста на пару пользователь-стрим, TTL — сколько клиент показывает индикатор
+type Typing struct {
+	Interval time.Duration `env:"CHAT_TYPING_INTERVAL" env-default:"3s"`
+	TTL      time.Duration `env:"CHAT_TYPING_TTL" env-default:"6s"`
 }
 
 func MustLoad() *Config {
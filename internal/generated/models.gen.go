@@ -3,6 +3,216 @@
 // Code generated by github.com/deepmap/oapi-codegen version v1.16.3 DO NOT EDIT.
 package api
 
+import (
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Defines values for NotifyLevel.
+const (
+	NotifyAll      NotifyLevel = "all"
+	NotifyMentions NotifyLevel = "mentions"
+	NotifyNone     NotifyLevel = "none"
+)
+
+// Defines values for SetStreamMemberRoleRequestRole.
+const (
+	Admin    SetStreamMemberRoleRequestRole = "admin"
+	Member   SetStreamMemberRoleRequestRole = "member"
+	ReadOnly SetStreamMemberRoleRequestRole = "read_only"
+)
+
+// Defines values for StreamPreviewType.
+const (
+	StreamPreviewTypeChannel StreamPreviewType = "channel"
+	StreamPreviewTypeComment StreamPreviewType = "comment"
+	StreamPreviewTypeGroup   StreamPreviewType = "group"
+	StreamPreviewTypePrivate StreamPreviewType = "private"
+)
+
+// Defines values for GetStreamsParamsType.
+const (
+	GetStreamsParamsTypeChannel GetStreamsParamsType = "channel"
+	GetStreamsParamsTypeComment GetStreamsParamsType = "comment"
+	GetStreamsParamsTypeGroup   GetStreamsParamsType = "group"
+	GetStreamsParamsTypePrivate GetStreamsParamsType = "private"
+)
+
+// Defines values for GetStreamRecentMessagesParamsDirection.
+const (
+	After  GetStreamRecentMessagesParamsDirection = "after"
+	Before GetStreamRecentMessagesParamsDirection = "before"
+)
+
+// Defines values for DeleteMessageParamsFormat.
+const (
+	All  DeleteMessageParamsFormat = "all"
+	Self DeleteMessageParamsFormat = "self"
+)
+
+// AddStreamMembersRequest defines model for AddStreamMembersRequest.
+type AddStreamMembersRequest struct {
+	Users []ChatUser `json:"users"`
+}
+
+// AddStreamMembersResponse defines model for AddStreamMembersResponse.
+type AddStreamMembersResponse struct {
+	// Added IDs of users that became members
+	Added []string `json:"added"`
+}
+
+// Attachment defines model for Attachment.
+type Attachment struct {
+	// Height Image height in pixels
+	Height *int32 `json:"height,omitempty"`
+
+	// MimeType MIME type of the file
+	MimeType string `json:"mime_type"`
+
+	// Size File size in bytes
+	Size int64 `json:"size"`
+
+	// StorageKey Key of the uploaded file in the blob storage
+	StorageKey string `json:"storage_key"`
+
+	// ThumbnailKey Key of the generated thumbnail in the blob storage
+	ThumbnailKey *string `json:"thumbnail_key,omitempty"`
+
+	// Width Image width in pixels
+	Width *int32 `json:"width,omitempty"`
+}
+
+// CentrifugoConnectRequest defines model for CentrifugoConnectRequest.
+type CentrifugoConnectRequest struct {
+	// Client Centrifugo client ID
+	Client    string  `json:"client"`
+	Encoding  *string `json:"encoding,omitempty"`
+	Protocol  *string `json:"protocol,omitempty"`
+	Transport *string `json:"transport,omitempty"`
+}
+
+// CentrifugoConnectResponse defines model for CentrifugoConnectResponse.
+type CentrifugoConnectResponse struct {
+	Disconnect *CentrifugoDisconnect    `json:"disconnect,omitempty"`
+	Error      *CentrifugoProxyError    `json:"error,omitempty"`
+	Result     *CentrifugoConnectResult `json:"result,omitempty"`
+}
+
+// CentrifugoConnectResult defines model for CentrifugoConnectResult.
+type CentrifugoConnectResult struct {
+	// Channels Server-side subscriptions
+	Channels *[]string `json:"channels,omitempty"`
+
+	// ExpireAt Unix time when Centrifugo calls the refresh proxy
+	ExpireAt *int64 `json:"expire_at,omitempty"`
+	User     string `json:"user"`
+}
+
+// CentrifugoDisconnect defines model for CentrifugoDisconnect.
+type CentrifugoDisconnect struct {
+	Code   uint32 `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// CentrifugoProxyError defines model for CentrifugoProxyError.
+type CentrifugoProxyError struct {
+	Code    uint32 `json:"code"`
+	Message string `json:"message"`
+}
+
+// CentrifugoProxyResponse defines model for CentrifugoProxyResponse.
+type CentrifugoProxyResponse struct {
+	Disconnect *CentrifugoDisconnect  `json:"disconnect,omitempty"`
+	Error      *CentrifugoProxyError  `json:"error,omitempty"`
+	Result     *CentrifugoProxyResult `json:"result,omitempty"`
+}
+
+// CentrifugoProxyResult defines model for CentrifugoProxyResult.
+type CentrifugoProxyResult = map[string]interface{}
+
+// CentrifugoPublishRequest defines model for CentrifugoPublishRequest.
+type CentrifugoPublishRequest struct {
+	Channel string `json:"channel"`
+
+	// Client Centrifugo client ID
+	Client string `json:"client"`
+
+	// Data Publication data sent by the client
+	Data      *interface{} `json:"data,omitempty"`
+	Encoding  *string      `json:"encoding,omitempty"`
+	Protocol  *string      `json:"protocol,omitempty"`
+	Transport *string      `json:"transport,omitempty"`
+
+	// User User ID set by the connect proxy
+	User string `json:"user"`
+}
+
+// CentrifugoRefreshRequest defines model for CentrifugoRefreshRequest.
+type CentrifugoRefreshRequest struct {
+	// Client Centrifugo client ID
+	Client    string  `json:"client"`
+	Encoding  *string `json:"encoding,omitempty"`
+	Protocol  *string `json:"protocol,omitempty"`
+	Transport *string `json:"transport,omitempty"`
+
+	// User User ID set by the connect proxy
+	User string `json:"user"`
+}
+
+// CentrifugoRefreshResponse defines model for CentrifugoRefreshResponse.
+type CentrifugoRefreshResponse struct {
+	Result *CentrifugoRefreshResult `json:"result,omitempty"`
+}
+
+// CentrifugoRefreshResult defines model for CentrifugoRefreshResult.
+type CentrifugoRefreshResult struct {
+	ExpireAt *int64 `json:"expire_at,omitempty"`
+	Expired  *bool  `json:"expired,omitempty"`
+}
+
+// CentrifugoSubscribeRequest defines model for CentrifugoSubscribeRequest.
+type CentrifugoSubscribeRequest struct {
+	Channel string `json:"channel"`
+
+	// Client Centrifugo client ID
+	Client    string  `json:"client"`
+	Encoding  *string `json:"encoding,omitempty"`
+	Protocol  *string `json:"protocol,omitempty"`
+	Transport *string `json:"transport,omitempty"`
+
+	// User User ID set by the connect proxy
+	User string `json:"user"`
+}
+
+// ChannelStream defines model for ChannelStream.
+type ChannelStream struct {
+	// AvatarUrl Channel avatar URL
+	AvatarUrl *string `json:"avatar_url,omitempty"`
+
+	// LastMessageContent Last message content
+	LastMessageContent *string `json:"last_message_content,omitempty"`
+
+	// LastMessageTimestamp Last message timestamp
+	LastMessageTimestamp *string `json:"last_message_timestamp,omitempty"`
+
+	// Muted Whether the stream is muted for the requester right now
+	Muted bool `json:"muted"`
+
+	// NotifyLevel Which messages of the stream produce notifications for the member
+	NotifyLevel NotifyLevel `json:"notify_level"`
+
+	// Role Role of the requester in the channel (owner, admin, member, read_only)
+	Role string `json:"role"`
+
+	// StreamId Stream ID
+	StreamId string `json:"stream_id"`
+
+	// StreamName Channel title
+	StreamName string `json:"stream_name"`
+
+	// UnreadCount Number of unread messages in the stream
+	UnreadCount int64 `json:"unread_count"`
+}
+
 // ChatUser defines model for ChatUser.
 type ChatUser struct {
 	// Id User ID
@@ -12,14 +222,26 @@ type ChatUser struct {
 	Metadata *string `json:"metadata,omitempty"`
 }
 
+// CompleteAttachmentRequest defines model for CompleteAttachmentRequest.
+type CompleteAttachmentRequest struct {
+	// StorageKey Key returned by the presign call
+	StorageKey string `json:"storage_key"`
+}
+
 // CreateStreamRequest defines model for CreateStreamRequest.
 type CreateStreamRequest struct {
+	// AvatarUrl Stream avatar URL (optional, group and channel streams only)
+	AvatarUrl *string `json:"avatar_url,omitempty"`
+
 	// ChatMetadata Chat metadata
 	ChatMetadata string `json:"chat_metadata"`
 
 	// CreatorMetadata Creator metadata
 	CreatorMetadata string `json:"creator_metadata"`
 
+	// Title Stream title (required for group and channel streams)
+	Title *string `json:"title,omitempty"`
+
 	// Type Stream type
 	Type  string     `json:"type"`
 	Users []ChatUser `json:"users"`
@@ -31,6 +253,30 @@ type CreateStreamResponse struct {
 	Id string `json:"id"`
 }
 
+// DeleteMessageResponse defines model for DeleteMessageResponse.
+type DeleteMessageResponse struct {
+	// Format Applied delete format
+	Format string `json:"format"`
+
+	// MessageId Deleted message ID
+	MessageId string `json:"message_id"`
+}
+
+// EditMessageRequest defines model for EditMessageRequest.
+type EditMessageRequest struct {
+	// Content New message content
+	Content string `json:"content"`
+}
+
+// EditMessageResponse defines model for EditMessageResponse.
+type EditMessageResponse struct {
+	// MessageId Edited message ID
+	MessageId string `json:"message_id"`
+
+	// UpdatedAt Update timestamp
+	UpdatedAt string `json:"updated_at"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	// Error Error message
@@ -48,23 +294,84 @@ type GetBatchSubscribeTokensResponse struct {
 	Subscriptions []StreamSubscription `json:"subscriptions"`
 }
 
+// GetChannelStreamsResponse defines model for GetChannelStreamsResponse.
+type GetChannelStreamsResponse struct {
+	Streams []ChannelStream `json:"streams"`
+}
+
 // GetConnectAccessTokenResponse defines model for GetConnectAccessTokenResponse.
 type GetConnectAccessTokenResponse struct {
 	// ExpiresAt Token expiration timestamp
 	ExpiresAt int64 `json:"expires_at"`
 
+	// PersonalChannel Personal user channel the connection is subscribed to server-side.
+	// Receives stream.added and stream.updated events (unread counters, stream list ordering),
+	// so clients don't need to subscribe to every stream to keep the stream list up to date.
+	PersonalChannel string `json:"personal_channel"`
+
 	// Token JWT token for Centrifugo connection
 	Token string `json:"token"`
 }
 
+// GetGroupStreamsResponse defines model for GetGroupStreamsResponse.
+type GetGroupStreamsResponse struct {
+	Streams []GroupStream `json:"streams"`
+}
+
+// GetMessageContextResponse defines model for GetMessageContextResponse.
+type GetMessageContextResponse struct {
+	// AfterCursor Cursor of the newest message in the window, for loading newer messages with direction=after
+	AfterCursor string `json:"after_cursor"`
+
+	// BeforeCursor Cursor of the oldest message in the window, for loading older messages with direction=before
+	BeforeCursor string `json:"before_cursor"`
+
+	// HasNewer Whether there are newer messages beyond the window
+	HasNewer bool `json:"has_newer"`
+
+	// HasOlder Whether there are older messages beyond the window
+	HasOlder bool `json:"has_older"`
+
+	// Messages Messages around the given one, including it, oldest first
+	Messages []Message `json:"messages"`
+}
+
+// GetMessageThreadResponse defines model for GetMessageThreadResponse.
+type GetMessageThreadResponse struct {
+	Messages []Message `json:"messages"`
+
+	// NextCursor Cursor for the next page of older replies, absent when there are no more replies
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// GetPrivateStreamWithResponse defines model for GetPrivateStreamWithResponse.
+type GetPrivateStreamWithResponse struct {
+	StreamId string `json:"stream_id"`
+}
+
 // GetPrivateStreamsResponse defines model for GetPrivateStreamsResponse.
 type GetPrivateStreamsResponse struct {
 	Streams []PrivateStream `json:"streams"`
 }
 
+// GetStreamPresenceResponse defines model for GetStreamPresenceResponse.
+type GetStreamPresenceResponse struct {
+	// NumClients Number of open connections subscribed to the stream
+	NumClients int `json:"num_clients"`
+
+	// NumUsers Number of distinct online users
+	NumUsers int `json:"num_users"`
+
+	// UserIds Online users
+	UserIds []string `json:"user_ids"`
+}
+
 // GetStreamRecentMessagesResponse defines model for GetStreamRecentMessagesResponse.
 type GetStreamRecentMessagesResponse struct {
 	Messages []Message `json:"messages"`
+
+	// NextCursor Cursor for the next page in the same direction, absent when there are no more messages
+	NextCursor *string `json:"next_cursor,omitempty"`
 }
 
 // GetStreamSubscribeTokenResponse defines model for GetStreamSubscribeTokenResponse.
@@ -79,20 +386,76 @@ type GetStreamSubscribeTokenResponse struct {
 	Token string `json:"token"`
 }
 
+// GetStreamsResponse defines model for GetStreamsResponse.
+type GetStreamsResponse struct {
+	// NextCursor Cursor for the next page, absent when there are no more streams
+	NextCursor *string         `json:"next_cursor,omitempty"`
+	Streams    []StreamPreview `json:"streams"`
+}
+
 // GetUserActiveStreamsResponse defines model for GetUserActiveStreamsResponse.
 type GetUserActiveStreamsResponse struct {
 	// StreamIds List of active stream IDs
 	StreamIds []string `json:"stream_ids"`
 }
 
+// GroupStream defines model for GroupStream.
+type GroupStream struct {
+	// AvatarUrl Group avatar URL
+	AvatarUrl *string `json:"avatar_url,omitempty"`
+
+	// LastMessageContent Last message content
+	LastMessageContent *string `json:"last_message_content,omitempty"`
+
+	// LastMessageTimestamp Last message timestamp
+	LastMessageTimestamp *string `json:"last_message_timestamp,omitempty"`
+
+	// Muted Whether the stream is muted for the requester right now
+	Muted bool `json:"muted"`
+
+	// NotifyLevel Which messages of the stream produce notifications for the member
+	NotifyLevel NotifyLevel `json:"notify_level"`
+
+	// StreamId Stream ID
+	StreamId string `json:"stream_id"`
+
+	// StreamName Group title
+	StreamName string `json:"stream_name"`
+
+	// UnreadCount Number of unread messages in the stream
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// MarkStreamReadRequest defines model for MarkStreamReadRequest.
+type MarkStreamReadRequest struct {
+	// MessageId ID of the last read message
+	MessageId string `json:"message_id"`
+}
+
+// MarkStreamReadResponse defines model for MarkStreamReadResponse.
+type MarkStreamReadResponse struct {
+	// Updated Whether the read position moved forward
+	Updated bool `json:"updated"`
+}
+
 // Message defines model for Message.
 type Message struct {
 	// Content Message content
-	Content string `json:"content"`
+	Content string        `json:"content"`
+	Media   *MessageMedia `json:"media,omitempty"`
+
+	// MessageType Message type
+	MessageType string `json:"message_type"`
 
 	// ParentUuid Parent message UUID
 	ParentUuid *string `json:"parent_uuid,omitempty"`
 
+	// ReadBy IDs of users who have read the message
+	ReadBy []string `json:"read_by"`
+
+	// ReplyCount Number of replies in the thread started by the message
+	ReplyCount int64 `json:"reply_count"`
+
 	// RootUuid Root message UUID
 	RootUuid *string `json:"root_uuid,omitempty"`
 
@@ -106,30 +469,112 @@ type Message struct {
 	Uuid string `json:"uuid"`
 }
 
+// MessageMedia defines model for MessageMedia.
+type MessageMedia struct {
+	// DurationMs Duration in milliseconds (video, speech, circle)
+	DurationMs *int64 `json:"duration_ms,omitempty"`
+
+	// Height Height in pixels (image, video, circle)
+	Height *int32 `json:"height,omitempty"`
+
+	// MimeType MIME type of the file
+	MimeType string `json:"mime_type"`
+
+	// Size File size in bytes
+	Size int64 `json:"size"`
+
+	// StorageKey Key of the uploaded file in the blob storage
+	StorageKey string `json:"storage_key"`
+
+	// ThumbnailKey Key of the generated thumbnail in the blob storage (image)
+	ThumbnailKey *string `json:"thumbnail_key,omitempty"`
+
+	// Waveform Waveform samples in range 0-255 (speech)
+	Waveform *[]int32 `json:"waveform,omitempty"`
+
+	// Width Width in pixels (image, video, circle)
+	Width *int32 `json:"width,omitempty"`
+}
+
+// MuteStreamRequest defines model for MuteStreamRequest.
+type MuteStreamRequest struct {
+	// Until End of the mute in RFC3339, must be in the future
+	Until *string `json:"until,omitempty"`
+}
+
+// NotifyLevel Which messages of the stream produce notifications for the member
+type NotifyLevel string
+
+// PresignAttachmentRequest defines model for PresignAttachmentRequest.
+type PresignAttachmentRequest struct {
+	// MimeType MIME type of the file
+	MimeType string `json:"mime_type"`
+
+	// Size Exact file size in bytes
+	Size int64 `json:"size"`
+}
+
+// PresignAttachmentResponse defines model for PresignAttachmentResponse.
+type PresignAttachmentResponse struct {
+	// ExpiresAt Upload URL expiration timestamp
+	ExpiresAt int64 `json:"expires_at"`
+
+	// Headers Headers that must be sent with the upload
+	Headers map[string]string `json:"headers"`
+
+	// Method HTTP method to use for the upload
+	Method string `json:"method"`
+
+	// StorageKey Key to reference from the message media
+	StorageKey string `json:"storage_key"`
+
+	// UploadUrl Presigned URL for uploading the file
+	UploadUrl string `json:"upload_url"`
+}
+
 // PrivateStream defines model for PrivateStream.
 type PrivateStream struct {
 	// AvatarUrl Stream avatar URL
 	AvatarUrl *string `json:"avatar_url,omitempty"`
 
+	// CompanionId ID of the other participant
+	CompanionId string `json:"companion_id"`
+
 	// LastMessageContent Last message content
 	LastMessageContent *string `json:"last_message_content,omitempty"`
 
 	// LastMessageTimestamp Last message timestamp
 	LastMessageTimestamp *string `json:"last_message_timestamp,omitempty"`
 
+	// LastOnline When the other participant was last seen online (RFC3339)
+	LastOnline *string `json:"last_online,omitempty"`
+
+	// Muted Whether the stream is muted for the requester right now
+	Muted bool `json:"muted"`
+
+	// NotifyLevel Which messages of the stream produce notifications for the member
+	NotifyLevel NotifyLevel `json:"notify_level"`
+
+	// Online Whether the other participant currently has an open Centrifugo connection
+	Online bool `json:"online"`
+
 	// StreamId Stream ID
 	StreamId string `json:"stream_id"`
 
 	// StreamName Stream name
 	StreamName string `json:"stream_name"`
+
+	// UnreadCount Number of unread messages in the stream
+	UnreadCount int64 `json:"unread_count"`
 }
 
 // SendMessageRequest defines model for SendMessageRequest.
 type SendMessageRequest struct {
 	// Content Message content
-	Content string `json:"content"`
+	Content string        `json:"content"`
+	Media   *MessageMedia `json:"media,omitempty"`
 
-	// MessageType Message type (text, image, file)
+	// MessageType Message type (text, image, video, file, speech, circle)
 	MessageType string `json:"message_type"`
 
 	// ParentId Parent message ID (optional for replies)
@@ -148,6 +593,88 @@ type SendMessageResponse struct {
 	SentAt string `json:"sent_at"`
 }
 
+// SendTypingResponse defines model for SendTypingResponse.
+type SendTypingResponse struct {
+	// ExpiresAt Unix time until which clients show the typing indicator
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// SetStreamMemberRoleRequest defines model for SetStreamMemberRoleRequest.
+type SetStreamMemberRoleRequest struct {
+	Role SetStreamMemberRoleRequestRole `json:"role"`
+}
+
+// SetStreamMemberRoleRequestRole defines model for SetStreamMemberRoleRequest.Role.
+type SetStreamMemberRoleRequestRole string
+
+// StreamLastMessage defines model for StreamLastMessage.
+type StreamLastMessage struct {
+	Content        *string `json:"content,omitempty"`
+	Id             string  `json:"id"`
+	SenderId       string  `json:"sender_id"`
+	SenderNickname *string `json:"sender_nickname,omitempty"`
+
+	// SentAt RFC3339
+	SentAt string `json:"sent_at"`
+}
+
+// StreamMemberResponse defines model for StreamMemberResponse.
+type StreamMemberResponse struct {
+	StreamId string `json:"stream_id"`
+	UserId   string `json:"user_id"`
+}
+
+// StreamMemberRoleResponse defines model for StreamMemberRoleResponse.
+type StreamMemberRoleResponse struct {
+	Role     string `json:"role"`
+	StreamId string `json:"stream_id"`
+	UserId   string `json:"user_id"`
+}
+
+// StreamNotificationsResponse defines model for StreamNotificationsResponse.
+type StreamNotificationsResponse struct {
+	// Level Which messages of the stream produce notifications for the member
+	Level NotifyLevel `json:"level"`
+	Muted bool        `json:"muted"`
+
+	// MutedUntil End of a temporary mute (RFC3339)
+	MutedUntil *string `json:"muted_until,omitempty"`
+	StreamId   string  `json:"stream_id"`
+}
+
+// StreamPreview defines model for StreamPreview.
+type StreamPreview struct {
+	AvatarUrl *string `json:"avatar_url,omitempty"`
+
+	// CompanionId ID of the other participant, present only for private streams
+	CompanionId *string `json:"companion_id,omitempty"`
+
+	// LastActivityAt Time of the last message or of stream creation when there are no messages (RFC3339)
+	LastActivityAt string             `json:"last_activity_at"`
+	LastMessage    *StreamLastMessage `json:"last_message,omitempty"`
+
+	// Muted Whether the stream is muted for the requester right now
+	Muted bool `json:"muted"`
+
+	// MutedUntil End of a temporary mute (RFC3339)
+	MutedUntil *string `json:"muted_until,omitempty"`
+
+	// NotifyLevel Which messages of the stream produce notifications for the member
+	NotifyLevel NotifyLevel `json:"notify_level"`
+
+	// Role Role of the requester in the stream
+	Role     string `json:"role"`
+	StreamId string `json:"stream_id"`
+
+	// StreamName Companion nickname for private streams, title otherwise
+	StreamName  string            `json:"stream_name"`
+	Type        StreamPreviewType `json:"type"`
+	UnreadCount int64             `json:"unread_count"`
+}
+
+// StreamPreviewType defines model for StreamPreview.Type.
+type StreamPreviewType string
+
 // StreamSubscription defines model for StreamSubscription.
 type StreamSubscription struct {
 	// Channel Centrifugo channel name
@@ -163,20 +690,162 @@ type StreamSubscription struct {
 	Token string `json:"token"`
 }
 
+// SubscribeToStreamResponse defines model for SubscribeToStreamResponse.
+type SubscribeToStreamResponse struct {
+	// StreamId Subscribed stream ID
+	StreamId string `json:"stream_id"`
+}
+
+// TransferStreamOwnershipRequest defines model for TransferStreamOwnershipRequest.
+type TransferStreamOwnershipRequest struct {
+	// UserId Member who becomes the new owner
+	UserId string `json:"user_id"`
+}
+
+// UpdateStreamNotificationsRequest defines model for UpdateStreamNotificationsRequest.
+type UpdateStreamNotificationsRequest struct {
+	// Level Which messages of the stream produce notifications for the member
+	Level NotifyLevel `json:"level"`
+}
+
+// UpdateStreamRequest defines model for UpdateStreamRequest.
+type UpdateStreamRequest struct {
+	AvatarUrl *string `json:"avatar_url,omitempty"`
+	Title     *string `json:"title,omitempty"`
+}
+
+// UpdateStreamResponse defines model for UpdateStreamResponse.
+type UpdateStreamResponse struct {
+	AvatarUrl *string `json:"avatar_url,omitempty"`
+	StreamId  string  `json:"stream_id"`
+	Title     string  `json:"title"`
+}
+
+// UploadAttachmentMultipartBody defines parameters for UploadAttachment.
+type UploadAttachmentMultipartBody struct {
+	File openapi_types.File `json:"file"`
+}
+
+// StreamEventsParams defines parameters for StreamEvents.
+type StreamEventsParams struct {
+	// StreamId Streams to follow; defaults to every stream the caller is a member of
+	StreamId *[]string `form:"stream_id,omitempty" json:"stream_id,omitempty"`
+
+	// LastEventId Resume after this event id when the Last-Event-ID header can't be set
+	LastEventId *string `form:"last_event_id,omitempty" json:"last_event_id,omitempty"`
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// GetStreamsParams defines parameters for GetStreams.
+type GetStreamsParams struct {
+	// Type Return only streams of this type
+	Type *GetStreamsParamsType `form:"type,omitempty" json:"type,omitempty"`
+
+	// Cursor Opaque cursor from next_cursor of a previous response
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Number of streams to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetStreamsParamsType defines parameters for GetStreams.
+type GetStreamsParamsType string
+
 // GetStreamRecentMessagesParams defines parameters for GetStreamRecentMessages.
 type GetStreamRecentMessagesParams struct {
-	// Offset Timestamp offset in RFC3339 format
+	// Cursor Opaque cursor from next_cursor of a previous response
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Direction Paging direction relative to the cursor. before returns older messages newest first, after returns newer messages oldest first
+	Direction *GetStreamRecentMessagesParamsDirection `form:"direction,omitempty" json:"direction,omitempty"`
+
+	// Offset Timestamp offset in RFC3339 format, superseded by cursor
 	Offset *string `form:"offset,omitempty" json:"offset,omitempty"`
 
 	// Limit Number of messages to return
 	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// GetStreamRecentMessagesParamsDirection defines parameters for GetStreamRecentMessages.
+type GetStreamRecentMessagesParamsDirection string
+
+// DeleteMessageParams defines parameters for DeleteMessage.
+type DeleteMessageParams struct {
+	// Format Delete format (self hides the message for the requester, all deletes it for everyone)
+	Format DeleteMessageParamsFormat `form:"format" json:"format"`
+}
+
+// DeleteMessageParamsFormat defines parameters for DeleteMessage.
+type DeleteMessageParamsFormat string
+
+// GetMessageContextParams defines parameters for GetMessageContext.
+type GetMessageContextParams struct {
+	// Limit Number of messages to return on each side of the given message
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetMessageThreadParams defines parameters for GetMessageThread.
+type GetMessageThreadParams struct {
+	// Cursor Opaque cursor from next_cursor of a previous response
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Offset Timestamp offset in RFC3339 format, superseded by cursor
+	Offset *string `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Limit Number of replies to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// UploadAttachmentMultipartRequestBody defines body for UploadAttachment for multipart/form-data ContentType.
+type UploadAttachmentMultipartRequestBody UploadAttachmentMultipartBody
+
+// CompleteAttachmentJSONRequestBody defines body for CompleteAttachment for application/json ContentType.
+type CompleteAttachmentJSONRequestBody = CompleteAttachmentRequest
+
+// PresignAttachmentJSONRequestBody defines body for PresignAttachment for application/json ContentType.
+type PresignAttachmentJSONRequestBody = PresignAttachmentRequest
+
 // CreateStreamJSONRequestBody defines body for CreateStream for application/json ContentType.
 type CreateStreamJSONRequestBody = CreateStreamRequest
 
+// UpdateStreamJSONRequestBody defines body for UpdateStream for application/json ContentType.
+type UpdateStreamJSONRequestBody = UpdateStreamRequest
+
+// AddStreamMembersJSONRequestBody defines body for AddStreamMembers for application/json ContentType.
+type AddStreamMembersJSONRequestBody = AddStreamMembersRequest
+
+// SetStreamMemberRoleJSONRequestBody defines body for SetStreamMemberRole for application/json ContentType.
+type SetStreamMemberRoleJSONRequestBody = SetStreamMemberRoleRequest
+
 // SendMessageJSONRequestBody defines body for SendMessage for application/json ContentType.
 type SendMessageJSONRequestBody = SendMessageRequest
 
+// EditMessageJSONRequestBody defines body for EditMessage for application/json ContentType.
+type EditMessageJSONRequestBody = EditMessageRequest
+
+// MuteStreamJSONRequestBody defines body for MuteStream for application/json ContentType.
+type MuteStreamJSONRequestBody = MuteStreamRequest
+
+// UpdateStreamNotificationsJSONRequestBody defines body for UpdateStreamNotifications for application/json ContentType.
+type UpdateStreamNotificationsJSONRequestBody = UpdateStreamNotificationsRequest
+
+// TransferStreamOwnershipJSONRequestBody defines body for TransferStreamOwnership for application/json ContentType.
+type TransferStreamOwnershipJSONRequestBody = TransferStreamOwnershipRequest
+
+// MarkStreamReadJSONRequestBody defines body for MarkStreamRead for application/json ContentType.
+type MarkStreamReadJSONRequestBody = MarkStreamReadRequest
+
 // GetBatchSubscribeTokensJSONRequestBody defines body for GetBatchSubscribeTokens for application/json ContentType.
 type GetBatchSubscribeTokensJSONRequestBody = GetBatchSubscribeTokensRequest
+
+// CentrifugoConnectJSONRequestBody defines body for CentrifugoConnect for application/json ContentType.
+type CentrifugoConnectJSONRequestBody = CentrifugoConnectRequest
+
+// CentrifugoPublishJSONRequestBody defines body for CentrifugoPublish for application/json ContentType.
+type CentrifugoPublishJSONRequestBody = CentrifugoPublishRequest
+
+// CentrifugoRefreshJSONRequestBody defines body for CentrifugoRefresh for application/json ContentType.
+type CentrifugoRefreshJSONRequestBody = CentrifugoRefreshRequest
+
+// CentrifugoSubscribeJSONRequestBody defines body for CentrifugoSubscribe for application/json ContentType.
+type CentrifugoSubscribeJSONRequestBody = CentrifugoSubscribeRequest
@@ -13,21 +13,96 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Upload an attachment directly through the service
+	// (POST /api/chat/attachments)
+	UploadAttachment(w http.ResponseWriter, r *http.Request)
+	// Confirm a presigned upload
+	// (POST /api/chat/attachments/complete)
+	CompleteAttachment(w http.ResponseWriter, r *http.Request)
+	// Get a presigned URL for uploading an attachment to the blob storage
+	// (POST /api/chat/attachments/presign)
+	PresignAttachment(w http.ResponseWriter, r *http.Request)
+	// Stream the caller's stream events as Server-Sent Events
+	// (GET /api/chat/events)
+	StreamEvents(w http.ResponseWriter, r *http.Request, params StreamEventsParams)
+	// Get user's streams of all types ordered by last activity
+	// (GET /api/chat/streams)
+	GetStreams(w http.ResponseWriter, r *http.Request, params GetStreamsParams)
 	// Create a new stream
 	// (POST /api/chat/streams)
 	CreateStream(w http.ResponseWriter, r *http.Request)
+	// Get channels the user is subscribed to
+	// (GET /api/chat/streams/channel)
+	GetChannelStreams(w http.ResponseWriter, r *http.Request)
+	// Get user's group streams
+	// (GET /api/chat/streams/group)
+	GetGroupStreams(w http.ResponseWriter, r *http.Request)
 	// Get user's private streams
 	// (GET /api/chat/streams/private)
 	GetPrivateStreams(w http.ResponseWriter, r *http.Request)
+	// Find the private stream with another user
+	// (GET /api/chat/streams/private/with/{user_id})
+	GetPrivateStreamWith(w http.ResponseWriter, r *http.Request, userId string)
+	// Update title or avatar of a group or channel stream
+	// (PATCH /api/chat/streams/{stream_id})
+	UpdateStream(w http.ResponseWriter, r *http.Request, streamId string)
+	// Leave a stream
+	// (POST /api/chat/streams/{stream_id}/leave)
+	LeaveStream(w http.ResponseWriter, r *http.Request, streamId string)
+	// Invite users to a group stream
+	// (POST /api/chat/streams/{stream_id}/members)
+	AddStreamMembers(w http.ResponseWriter, r *http.Request, streamId string)
+	// Remove a member from a group or channel stream
+	// (DELETE /api/chat/streams/{stream_id}/members/{user_id})
+	RemoveStreamMember(w http.ResponseWriter, r *http.Request, streamId string, userId string)
+	// Change the role of a stream member
+	// (PUT /api/chat/streams/{stream_id}/members/{user_id}/role)
+	SetStreamMemberRole(w http.ResponseWriter, r *http.Request, streamId string, userId string)
 	// Get recent messages from a stream
 	// (GET /api/chat/streams/{stream_id}/messages)
 	GetStreamRecentMessages(w http.ResponseWriter, r *http.Request, streamId string, params GetStreamRecentMessagesParams)
 	// Send a message to a stream
 	// (POST /api/chat/streams/{stream_id}/messages)
 	SendMessage(w http.ResponseWriter, r *http.Request, streamId string)
+	// Delete a message for the requester or for everyone
+	// (DELETE /api/chat/streams/{stream_id}/messages/{message_id})
+	DeleteMessage(w http.ResponseWriter, r *http.Request, streamId string, messageId string, params DeleteMessageParams)
+	// Edit a message
+	// (PATCH /api/chat/streams/{stream_id}/messages/{message_id})
+	EditMessage(w http.ResponseWriter, r *http.Request, streamId string, messageId string)
+	// Get a window of messages around the given message
+	// (GET /api/chat/streams/{stream_id}/messages/{message_id}/context)
+	GetMessageContext(w http.ResponseWriter, r *http.Request, streamId string, messageId string, params GetMessageContextParams)
+	// Get replies of a message thread
+	// (GET /api/chat/streams/{stream_id}/messages/{root_id}/thread)
+	GetMessageThread(w http.ResponseWriter, r *http.Request, streamId string, rootId string, params GetMessageThreadParams)
+	// Unmute the stream for the requester
+	// (DELETE /api/chat/streams/{stream_id}/mute)
+	UnmuteStream(w http.ResponseWriter, r *http.Request, streamId string)
+	// Mute the stream for the requester
+	// (POST /api/chat/streams/{stream_id}/mute)
+	MuteStream(w http.ResponseWriter, r *http.Request, streamId string)
+	// Set the notification level of the stream for the requester
+	// (PUT /api/chat/streams/{stream_id}/notifications)
+	UpdateStreamNotifications(w http.ResponseWriter, r *http.Request, streamId string)
+	// Transfer stream ownership to another member
+	// (POST /api/chat/streams/{stream_id}/owner)
+	TransferStreamOwnership(w http.ResponseWriter, r *http.Request, streamId string)
+	// Get who is currently online in a stream
+	// (GET /api/chat/streams/{stream_id}/presence)
+	GetStreamPresence(w http.ResponseWriter, r *http.Request, streamId string)
+	// Mark stream messages as read up to the given message
+	// (POST /api/chat/streams/{stream_id}/read)
+	MarkStreamRead(w http.ResponseWriter, r *http.Request, streamId string)
+	// Subscribe to a channel or comment stream
+	// (POST /api/chat/streams/{stream_id}/subscribe)
+	SubscribeToStream(w http.ResponseWriter, r *http.Request, streamId string)
 	// Get subscribe token for a specific stream
 	// (GET /api/chat/streams/{stream_id}/tokens/subscribe)
 	GetStreamSubscribeToken(w http.ResponseWriter, r *http.Request, streamId string)
+	// Tell other members that the user is typing
+	// (POST /api/chat/streams/{stream_id}/typing)
+	SendTyping(w http.ResponseWriter, r *http.Request, streamId string)
 	// Get Centrifugo connection token
 	// (GET /api/chat/tokens/connect)
 	GetConnectAccessToken(w http.ResponseWriter, r *http.Request)
@@ -37,75 +112,961 @@ type ServerInterface interface {
 	// Get user's active streams
 	// (GET /api/chat/user/streams)
 	GetUserActiveStreams(w http.ResponseWriter, r *http.Request)
+	// Centrifugo connect proxy
+	// (POST /centrifugo/connect)
+	CentrifugoConnect(w http.ResponseWriter, r *http.Request)
+	// Centrifugo publish proxy
+	// (POST /centrifugo/publish)
+	CentrifugoPublish(w http.ResponseWriter, r *http.Request)
+	// Centrifugo connection refresh proxy
+	// (POST /centrifugo/refresh)
+	CentrifugoRefresh(w http.ResponseWriter, r *http.Request)
+	// Centrifugo subscribe proxy
+	// (POST /centrifugo/subscribe)
+	CentrifugoSubscribe(w http.ResponseWriter, r *http.Request)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// Upload an attachment directly through the service
+// (POST /api/chat/attachments)
+func (_ Unimplemented) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Confirm a presigned upload
+// (POST /api/chat/attachments/complete)
+func (_ Unimplemented) CompleteAttachment(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get a presigned URL for uploading an attachment to the blob storage
+// (POST /api/chat/attachments/presign)
+func (_ Unimplemented) PresignAttachment(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Stream the caller's stream events as Server-Sent Events
+// (GET /api/chat/events)
+func (_ Unimplemented) StreamEvents(w http.ResponseWriter, r *http.Request, params StreamEventsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get user's streams of all types ordered by last activity
+// (GET /api/chat/streams)
+func (_ Unimplemented) GetStreams(w http.ResponseWriter, r *http.Request, params GetStreamsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Create a new stream
 // (POST /api/chat/streams)
 func (_ Unimplemented) CreateStream(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Get channels the user is subscribed to
+// (GET /api/chat/streams/channel)
+func (_ Unimplemented) GetChannelStreams(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get user's group streams
+// (GET /api/chat/streams/group)
+func (_ Unimplemented) GetGroupStreams(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Get user's private streams
 // (GET /api/chat/streams/private)
 func (_ Unimplemented) GetPrivateStreams(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Find the private stream with another user
+// (GET /api/chat/streams/private/with/{user_id})
+func (_ Unimplemented) GetPrivateStreamWith(w http.ResponseWriter, r *http.Request, userId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Update title or avatar of a group or channel stream
+// (PATCH /api/chat/streams/{stream_id})
+func (_ Unimplemented) UpdateStream(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Leave a stream
+// (POST /api/chat/streams/{stream_id}/leave)
+func (_ Unimplemented) LeaveStream(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Invite users to a group stream
+// (POST /api/chat/streams/{stream_id}/members)
+func (_ Unimplemented) AddStreamMembers(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Remove a member from a group or channel stream
+// (DELETE /api/chat/streams/{stream_id}/members/{user_id})
+func (_ Unimplemented) RemoveStreamMember(w http.ResponseWriter, r *http.Request, streamId string, userId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Change the role of a stream member
+// (PUT /api/chat/streams/{stream_id}/members/{user_id}/role)
+func (_ Unimplemented) SetStreamMemberRole(w http.ResponseWriter, r *http.Request, streamId string, userId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Get recent messages from a stream
 // (GET /api/chat/streams/{stream_id}/messages)
 func (_ Unimplemented) GetStreamRecentMessages(w http.ResponseWriter, r *http.Request, streamId string, params GetStreamRecentMessagesParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Send a message to a stream
-// (POST /api/chat/streams/{stream_id}/messages)
-func (_ Unimplemented) SendMessage(w http.ResponseWriter, r *http.Request, streamId string) {
-	w.WriteHeader(http.StatusNotImplemented)
+// Send a message to a stream
+// (POST /api/chat/streams/{stream_id}/messages)
+func (_ Unimplemented) SendMessage(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Delete a message for the requester or for everyone
+// (DELETE /api/chat/streams/{stream_id}/messages/{message_id})
+func (_ Unimplemented) DeleteMessage(w http.ResponseWriter, r *http.Request, streamId string, messageId string, params DeleteMessageParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Edit a message
+// (PATCH /api/chat/streams/{stream_id}/messages/{message_id})
+func (_ Unimplemented) EditMessage(w http.ResponseWriter, r *http.Request, streamId string, messageId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get a window of messages around the given message
+// (GET /api/chat/streams/{stream_id}/messages/{message_id}/context)
+func (_ Unimplemented) GetMessageContext(w http.ResponseWriter, r *http.Request, streamId string, messageId string, params GetMessageContextParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get replies of a message thread
+// (GET /api/chat/streams/{stream_id}/messages/{root_id}/thread)
+func (_ Unimplemented) GetMessageThread(w http.ResponseWriter, r *http.Request, streamId string, rootId string, params GetMessageThreadParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Unmute the stream for the requester
+// (DELETE /api/chat/streams/{stream_id}/mute)
+func (_ Unimplemented) UnmuteStream(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Mute the stream for the requester
+// (POST /api/chat/streams/{stream_id}/mute)
+func (_ Unimplemented) MuteStream(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Set the notification level of the stream for the requester
+// (PUT /api/chat/streams/{stream_id}/notifications)
+func (_ Unimplemented) UpdateStreamNotifications(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Transfer stream ownership to another member
+// (POST /api/chat/streams/{stream_id}/owner)
+func (_ Unimplemented) TransferStreamOwnership(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get who is currently online in a stream
+// (GET /api/chat/streams/{stream_id}/presence)
+func (_ Unimplemented) GetStreamPresence(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Mark stream messages as read up to the given message
+// (POST /api/chat/streams/{stream_id}/read)
+func (_ Unimplemented) MarkStreamRead(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Subscribe to a channel or comment stream
+// (POST /api/chat/streams/{stream_id}/subscribe)
+func (_ Unimplemented) SubscribeToStream(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get subscribe token for a specific stream
+// (GET /api/chat/streams/{stream_id}/tokens/subscribe)
+func (_ Unimplemented) GetStreamSubscribeToken(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Tell other members that the user is typing
+// (POST /api/chat/streams/{stream_id}/typing)
+func (_ Unimplemented) SendTyping(w http.ResponseWriter, r *http.Request, streamId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get Centrifugo connection token
+// (GET /api/chat/tokens/connect)
+func (_ Unimplemented) GetConnectAccessToken(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get batch subscribe tokens for multiple streams
+// (POST /api/chat/tokens/subscribe/batch)
+func (_ Unimplemented) GetBatchSubscribeTokens(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get user's active streams
+// (GET /api/chat/user/streams)
+func (_ Unimplemented) GetUserActiveStreams(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Centrifugo connect proxy
+// (POST /centrifugo/connect)
+func (_ Unimplemented) CentrifugoConnect(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Centrifugo publish proxy
+// (POST /centrifugo/publish)
+func (_ Unimplemented) CentrifugoPublish(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Centrifugo connection refresh proxy
+// (POST /centrifugo/refresh)
+func (_ Unimplemented) CentrifugoRefresh(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Centrifugo subscribe proxy
+// (POST /centrifugo/subscribe)
+func (_ Unimplemented) CentrifugoSubscribe(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// UploadAttachment operation middleware
+func (siw *ServerInterfaceWrapper) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UploadAttachment(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CompleteAttachment operation middleware
+func (siw *ServerInterfaceWrapper) CompleteAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CompleteAttachment(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PresignAttachment operation middleware
+func (siw *ServerInterfaceWrapper) PresignAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PresignAttachment(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StreamEvents operation middleware
+func (siw *ServerInterfaceWrapper) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamEventsParams
+
+	// ------------- Optional query parameter "stream_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "stream_id", r.URL.Query(), &params.StreamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "last_event_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "last_event_id", r.URL.Query(), &params.LastEventId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "last_event_id", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Last-Event-ID", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, valueList[0], &LastEventID)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Last-Event-ID", Err: err})
+			return
+		}
+
+		params.LastEventID = &LastEventID
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StreamEvents(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetStreams operation middleware
+func (siw *ServerInterfaceWrapper) GetStreams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStreamsParams
+
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", r.URL.Query(), &params.Type)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "type", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStreams(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateStream operation middleware
+func (siw *ServerInterfaceWrapper) CreateStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateStream(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetChannelStreams operation middleware
+func (siw *ServerInterfaceWrapper) GetChannelStreams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetChannelStreams(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetGroupStreams operation middleware
+func (siw *ServerInterfaceWrapper) GetGroupStreams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGroupStreams(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetPrivateStreams operation middleware
+func (siw *ServerInterfaceWrapper) GetPrivateStreams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPrivateStreams(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetPrivateStreamWith operation middleware
+func (siw *ServerInterfaceWrapper) GetPrivateStreamWith(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "user_id" -------------
+	var userId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "user_id", runtime.ParamLocationPath, chi.URLParam(r, "user_id"), &userId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPrivateStreamWith(w, r, userId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateStream operation middleware
+func (siw *ServerInterfaceWrapper) UpdateStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateStream(w, r, streamId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LeaveStream operation middleware
+func (siw *ServerInterfaceWrapper) LeaveStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LeaveStream(w, r, streamId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// AddStreamMembers operation middleware
+func (siw *ServerInterfaceWrapper) AddStreamMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AddStreamMembers(w, r, streamId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RemoveStreamMember operation middleware
+func (siw *ServerInterfaceWrapper) RemoveStreamMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "user_id" -------------
+	var userId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "user_id", runtime.ParamLocationPath, chi.URLParam(r, "user_id"), &userId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RemoveStreamMember(w, r, streamId, userId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetStreamMemberRole operation middleware
+func (siw *ServerInterfaceWrapper) SetStreamMemberRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "user_id" -------------
+	var userId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "user_id", runtime.ParamLocationPath, chi.URLParam(r, "user_id"), &userId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetStreamMemberRole(w, r, streamId, userId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetStreamRecentMessages operation middleware
+func (siw *ServerInterfaceWrapper) GetStreamRecentMessages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStreamRecentMessagesParams
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "direction" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "direction", r.URL.Query(), &params.Direction)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "direction", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStreamRecentMessages(w, r, streamId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SendMessage operation middleware
+func (siw *ServerInterfaceWrapper) SendMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SendMessage(w, r, streamId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteMessage operation middleware
+func (siw *ServerInterfaceWrapper) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "message_id" -------------
+	var messageId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "message_id", runtime.ParamLocationPath, chi.URLParam(r, "message_id"), &messageId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "message_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteMessageParams
+
+	// ------------- Required query parameter "format" -------------
+
+	if paramValue := r.URL.Query().Get("format"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "format"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteMessage(w, r, streamId, messageId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// EditMessage operation middleware
+func (siw *ServerInterfaceWrapper) EditMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "message_id" -------------
+	var messageId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "message_id", runtime.ParamLocationPath, chi.URLParam(r, "message_id"), &messageId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "message_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EditMessage(w, r, streamId, messageId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetMessageContext operation middleware
+func (siw *ServerInterfaceWrapper) GetMessageContext(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "message_id" -------------
+	var messageId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "message_id", runtime.ParamLocationPath, chi.URLParam(r, "message_id"), &messageId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "message_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMessageContextParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMessageContext(w, r, streamId, messageId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetMessageThread operation middleware
+func (siw *ServerInterfaceWrapper) GetMessageThread(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "root_id" -------------
+	var rootId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "root_id", runtime.ParamLocationPath, chi.URLParam(r, "root_id"), &rootId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "root_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMessageThreadParams
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMessageThread(w, r, streamId, rootId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// Get subscribe token for a specific stream
-// (GET /api/chat/streams/{stream_id}/tokens/subscribe)
-func (_ Unimplemented) GetStreamSubscribeToken(w http.ResponseWriter, r *http.Request, streamId string) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+// UnmuteStream operation middleware
+func (siw *ServerInterfaceWrapper) UnmuteStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-// Get Centrifugo connection token
-// (GET /api/chat/tokens/connect)
-func (_ Unimplemented) GetConnectAccessToken(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	var err error
 
-// Get batch subscribe tokens for multiple streams
-// (POST /api/chat/tokens/subscribe/batch)
-func (_ Unimplemented) GetBatchSubscribeTokens(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
 
-// Get user's active streams
-// (GET /api/chat/user/streams)
-func (_ Unimplemented) GetUserActiveStreams(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnmuteStream(w, r, streamId)
+	}))
 
-type MiddlewareFunc func(http.Handler) http.Handler
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-// CreateStream operation middleware
-func (siw *ServerInterfaceWrapper) CreateStream(w http.ResponseWriter, r *http.Request) {
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// MuteStream operation middleware
+func (siw *ServerInterfaceWrapper) MuteStream(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateStream(w, r)
+		siw.Handler.MuteStream(w, r, streamId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -115,12 +1076,23 @@ func (siw *ServerInterfaceWrapper) CreateStream(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetPrivateStreams operation middleware
-func (siw *ServerInterfaceWrapper) GetPrivateStreams(w http.ResponseWriter, r *http.Request) {
+// UpdateStreamNotifications operation middleware
+func (siw *ServerInterfaceWrapper) UpdateStreamNotifications(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetPrivateStreams(w, r)
+		siw.Handler.UpdateStreamNotifications(w, r, streamId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -130,8 +1102,8 @@ func (siw *ServerInterfaceWrapper) GetPrivateStreams(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetStreamRecentMessages operation middleware
-func (siw *ServerInterfaceWrapper) GetStreamRecentMessages(w http.ResponseWriter, r *http.Request) {
+// TransferStreamOwnership operation middleware
+func (siw *ServerInterfaceWrapper) TransferStreamOwnership(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -145,27 +1117,60 @@ func (siw *ServerInterfaceWrapper) GetStreamRecentMessages(w http.ResponseWriter
 		return
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetStreamRecentMessagesParams
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.TransferStreamOwnership(w, r, streamId)
+	}))
 
-	// ------------- Optional query parameter "offset" -------------
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetStreamPresence operation middleware
+func (siw *ServerInterfaceWrapper) GetStreamPresence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "limit" -------------
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStreamPresence(w, r, streamId)
+	}))
 
-	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// MarkStreamRead operation middleware
+func (siw *ServerInterfaceWrapper) MarkStreamRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetStreamRecentMessages(w, r, streamId, params)
+		siw.Handler.MarkStreamRead(w, r, streamId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -175,8 +1180,8 @@ func (siw *ServerInterfaceWrapper) GetStreamRecentMessages(w http.ResponseWriter
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// SendMessage operation middleware
-func (siw *ServerInterfaceWrapper) SendMessage(w http.ResponseWriter, r *http.Request) {
+// SubscribeToStream operation middleware
+func (siw *ServerInterfaceWrapper) SubscribeToStream(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -191,7 +1196,7 @@ func (siw *ServerInterfaceWrapper) SendMessage(w http.ResponseWriter, r *http.Re
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.SendMessage(w, r, streamId)
+		siw.Handler.SubscribeToStream(w, r, streamId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -227,6 +1232,32 @@ func (siw *ServerInterfaceWrapper) GetStreamSubscribeToken(w http.ResponseWriter
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// SendTyping operation middleware
+func (siw *ServerInterfaceWrapper) SendTyping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "stream_id" -------------
+	var streamId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "stream_id", runtime.ParamLocationPath, chi.URLParam(r, "stream_id"), &streamId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stream_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SendTyping(w, r, streamId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // GetConnectAccessToken operation middleware
 func (siw *ServerInterfaceWrapper) GetConnectAccessToken(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -272,6 +1303,66 @@ func (siw *ServerInterfaceWrapper) GetUserActiveStreams(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// CentrifugoConnect operation middleware
+func (siw *ServerInterfaceWrapper) CentrifugoConnect(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CentrifugoConnect(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CentrifugoPublish operation middleware
+func (siw *ServerInterfaceWrapper) CentrifugoPublish(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CentrifugoPublish(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CentrifugoRefresh operation middleware
+func (siw *ServerInterfaceWrapper) CentrifugoRefresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CentrifugoRefresh(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CentrifugoSubscribe operation middleware
+func (siw *ServerInterfaceWrapper) CentrifugoSubscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CentrifugoSubscribe(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -385,21 +1476,96 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/attachments", wrapper.UploadAttachment)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/attachments/complete", wrapper.CompleteAttachment)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/attachments/presign", wrapper.PresignAttachment)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/chat/events", wrapper.StreamEvents)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/chat/streams", wrapper.GetStreams)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/api/chat/streams", wrapper.CreateStream)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/chat/streams/channel", wrapper.GetChannelStreams)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/chat/streams/group", wrapper.GetGroupStreams)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/api/chat/streams/private", wrapper.GetPrivateStreams)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/chat/streams/private/with/{user_id}", wrapper.GetPrivateStreamWith)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/api/chat/streams/{stream_id}", wrapper.UpdateStream)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/streams/{stream_id}/leave", wrapper.LeaveStream)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/streams/{stream_id}/members", wrapper.AddStreamMembers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/api/chat/streams/{stream_id}/members/{user_id}", wrapper.RemoveStreamMember)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/api/chat/streams/{stream_id}/members/{user_id}/role", wrapper.SetStreamMemberRole)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/api/chat/streams/{stream_id}/messages", wrapper.GetStreamRecentMessages)
 	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/api/chat/streams/{stream_id}/messages", wrapper.SendMessage)
 	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/api/chat/streams/{stream_id}/messages/{message_id}", wrapper.DeleteMessage)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/api/chat/streams/{stream_id}/messages/{message_id}", wrapper.EditMessage)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/chat/streams/{stream_id}/messages/{message_id}/context", wrapper.GetMessageContext)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/chat/streams/{stream_id}/messages/{root_id}/thread", wrapper.GetMessageThread)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/api/chat/streams/{stream_id}/mute", wrapper.UnmuteStream)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/streams/{stream_id}/mute", wrapper.MuteStream)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/api/chat/streams/{stream_id}/notifications", wrapper.UpdateStreamNotifications)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/streams/{stream_id}/owner", wrapper.TransferStreamOwnership)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/chat/streams/{stream_id}/presence", wrapper.GetStreamPresence)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/streams/{stream_id}/read", wrapper.MarkStreamRead)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/streams/{stream_id}/subscribe", wrapper.SubscribeToStream)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/api/chat/streams/{stream_id}/tokens/subscribe", wrapper.GetStreamSubscribeToken)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/chat/streams/{stream_id}/typing", wrapper.SendTyping)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/api/chat/tokens/connect", wrapper.GetConnectAccessToken)
 	})
@@ -409,6 +1575,18 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/api/chat/user/streams", wrapper.GetUserActiveStreams)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/centrifugo/connect", wrapper.CentrifugoConnect)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/centrifugo/publish", wrapper.CentrifugoPublish)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/centrifugo/refresh", wrapper.CentrifugoRefresh)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/centrifugo/subscribe", wrapper.CentrifugoSubscribe)
+	})
 
 	return r
 }
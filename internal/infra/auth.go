@@ -2,6 +2,7 @@ package infra
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -21,8 +22,16 @@ func AuthInterceptorGRPC(
 	return handler(ctx, req)
 }
 
+// CentrifugoProxyPrefix — пути proxy-хуков Centrifugo, пользователь в них приходит от самой Centrifugo
+const CentrifugoProxyPrefix = "/centrifugo/"
+
 func AuthInterceptorHTTP(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, CentrifugoProxyPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		userID := r.Header.Get("X-User-ID")
 		userID = strings.TrimSpace(userID)
 
@@ -38,6 +47,29 @@ func AuthInterceptorHTTP(next http.Handler) http.Handler {
 	})
 }
 
+func CentrifugoProxyAuthHTTP(next http.Handler, proxyKey string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, CentrifugoProxyPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// без настроенного ключа proxy-хуки закрыты: иначе любой клиент мог бы представиться Centrifugo
+		if proxyKey == "" {
+			writeErrorResponse(w, "centrifugo proxy key is not configured", http.StatusUnauthorized)
+			return
+		}
+
+		key := r.Header.Get("X-Centrifugo-Proxy-Key")
+		if subtle.ConstantTimeCompare([]byte(key), []byte(proxyKey)) != 1 {
+			writeErrorResponse(w, "invalid proxy key", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
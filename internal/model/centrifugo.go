@@ -1,6 +1,10 @@
 package model
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"encoding/json"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 type CentrifugoEvent struct {
 	Method string      `json:"method"`
@@ -8,12 +12,58 @@ type CentrifugoEvent struct {
 }
 
 type CentrifugoEventParams struct {
-	Channel string  `json:"channel"`
-	Data    Message `json:"data"`
+	Channel string      `json:"channel"`
+	Data    interface{} `json:"data"`
+}
+
+type CentrifugoBroadcastParams struct {
+	Channels []string    `json:"channels"`
+	Data     interface{} `json:"data"`
+}
+
+// CentrifugoBroadcastResult — ответы по каждому каналу в порядке запроса
+type CentrifugoBroadcastResult struct {
+	Responses []CentrifugoResponse `json:"responses"`
+}
+
+type CentrifugoChannelParams struct {
+	Channel string `json:"channel"`
+}
+
+type CentrifugoUnsubscribeParams struct {
+	User    string `json:"user"`
+	Channel string `json:"channel"`
+}
+
+type CentrifugoResponse struct {
+	Result json.RawMessage  `json:"result,omitempty"`
+	Error  *CentrifugoError `json:"error,omitempty"`
+}
+
+type CentrifugoError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type CentrifugoClientInfo struct {
+	Client string `json:"client"`
+	User   string `json:"user"`
+}
+
+type CentrifugoPresenceResult struct {
+	Presence map[string]CentrifugoClientInfo `json:"presence"`
+}
+
+type PresenceStats struct {
+	NumClients int `json:"num_clients"`
+	NumUsers   int `json:"num_users"`
 }
 
 type CentrifugoConnectClaims struct {
 	jwt.RegisteredClaims
+
+	// Серверные подписки, на которые Centrifugo подпишет соединение сразу
+	Channels []string `json:"channels,omitempty"`
 }
 
 type CentrifugoSubscribeClaims struct {
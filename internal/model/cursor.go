@@ -0,0 +1,168 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BeforeCursorDirection = "before"
+	AfterCursorDirection  = "after"
+)
+
+// MessageCursor задаёт позицию в ленте сообщений. Пара (sent_at, id) уникальна,
+// поэтому сообщения с одинаковым временем отправки не теряются и не дублируются.
+type MessageCursor struct {
+	SentAt time.Time
+	ID     uuid.UUID
+}
+
+func NewMessageCursor(message Message) MessageCursor {
+	return MessageCursor{
+		SentAt: message.SentAt,
+		ID:     message.ID,
+	}
+}
+
+// CursorFromTimestamp преобразует устаревший offset в формате RFC3339 в курсор,
+// сохраняя прежнюю семантику sent_at <= offset.
+func CursorFromTimestamp(timestamp string) (MessageCursor, error) {
+	sentAt, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return MessageCursor{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	return MessageCursor{
+		SentAt: sentAt,
+		ID:     uuid.Max,
+	}, nil
+}
+
+// Before сообщает, стоит ли позиция раньше other в ленте; порядок совпадает с сортировкой (sent_at, id) в БД
+func (c MessageCursor) Before(other MessageCursor) bool {
+	if !c.SentAt.Equal(other.SentAt) {
+		return c.SentAt.Before(other.SentAt)
+	}
+
+	return bytes.Compare(c.ID[:], other.ID[:]) < 0
+}
+
+func (c MessageCursor) Encode() string {
+	return encodeCursor(c.SentAt, c.ID)
+}
+
+func ParseMessageCursor(encoded string) (MessageCursor, error) {
+	sentAt, messageID, err := parseCursor(encoded)
+	if err != nil {
+		return MessageCursor{}, err
+	}
+
+	return MessageCursor{
+		SentAt: sentAt,
+		ID:     messageID,
+	}, nil
+}
+
+// StreamCursor задаёт позицию в списке стримов, отсортированном по последней активности
+type StreamCursor struct {
+	ActivityAt time.Time
+	ID         uuid.UUID
+}
+
+func NewStreamCursor(stream StreamPreview) (StreamCursor, error) {
+	streamID, err := uuid.Parse(stream.StreamID)
+	if err != nil {
+		return StreamCursor{}, fmt.Errorf("invalid stream id: %w", err)
+	}
+
+	return StreamCursor{
+		ActivityAt: stream.ActivityAt,
+		ID:         streamID,
+	}, nil
+}
+
+func (c StreamCursor) Encode() string {
+	return encodeCursor(c.ActivityAt, c.ID)
+}
+
+func ParseStreamCursor(encoded string) (StreamCursor, error) {
+	activityAt, streamID, err := parseCursor(encoded)
+	if err != nil {
+		return StreamCursor{}, err
+	}
+
+	return StreamCursor{
+		ActivityAt: activityAt,
+		ID:         streamID,
+	}, nil
+}
+
+// EventCursor задаёт позицию в журнале событий, который упорядочен по (xid, id). Читаются только события
+// транзакций с xid меньше xmin текущего снимка: все они уже завершены, а ещё не закоммиченная транзакция
+// получит позицию после любой выданной, поэтому её события не пропадут
+type EventCursor struct {
+	Xid uint64
+	ID  int64
+}
+
+// Encode возвращает курсор в виде id события SSE
+func (c EventCursor) Encode() string {
+	return strconv.FormatUint(c.Xid, 10) + "-" + strconv.FormatInt(c.ID, 10)
+}
+
+func ParseEventCursor(encoded string) (EventCursor, error) {
+	xid, id, ok := strings.Cut(encoded, "-")
+	if !ok {
+		return EventCursor{}, fmt.Errorf("invalid event cursor format")
+	}
+
+	parsedXid, err := strconv.ParseUint(xid, 10, 64)
+	if err != nil {
+		return EventCursor{}, fmt.Errorf("invalid event cursor xid: %w", err)
+	}
+
+	parsedID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || parsedID < 0 {
+		return EventCursor{}, fmt.Errorf("invalid event cursor id: %s", id)
+	}
+
+	return EventCursor{
+		Xid: parsedXid,
+		ID:  parsedID,
+	}, nil
+}
+
+func encodeCursor(at time.Time, id uuid.UUID) string {
+	raw := strconv.FormatInt(at.UnixMicro(), 10) + ":" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func parseCursor(encoded string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	timestamp, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor format")
+	}
+
+	micros, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor timestamp: %w", err)
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor id: %w", err)
+	}
+
+	return time.UnixMicro(micros).UTC(), parsedID, nil
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventCursor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round_trip", func(t *testing.T) {
+		cursor := EventCursor{Xid: 1000, ID: 42}
+
+		assert.Equal(t, "1000-42", cursor.Encode())
+
+		parsed, err := ParseEventCursor(cursor.Encode())
+		require.NoError(t, err)
+		assert.Equal(t, cursor, parsed)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, encoded := range []string{"", "42", "x-42", "1000-x", "1000--1"} {
+			_, err := ParseEventCursor(encoded)
+			assert.Error(t, err, encoded)
+		}
+	})
+}
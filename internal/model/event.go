@@ -0,0 +1,116 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventVersion увеличивается при несовместимых изменениях формата payload
+const EventVersion = 1
+
+const (
+	MessageCreatedEvent = "message.created"
+	MessageEditedEvent  = "message.edited"
+	MessageDeletedEvent = "message.deleted"
+	MessagesReadEvent   = "messages.read"
+
+	// системные события состава стрима
+	MemberJoinedEvent      = "member.joined"
+	MemberLeftEvent        = "member.left"
+	MemberRoleChangedEvent = "member.role_changed"
+	StreamEditedEvent      = "stream.edited"
+
+	// UserTypingEvent эфемерное: публикуется напрямую, минуя outbox
+	UserTypingEvent = "user.typing"
+)
+
+// События персонального канала пользователя
+const (
+	StreamAddedEvent   = "stream.added"
+	StreamUpdatedEvent = "stream.updated"
+	StreamRemovedEvent = "stream.removed"
+)
+
+// EventEnvelope — формат всех публикаций в Centrifugo
+type EventEnvelope struct {
+	Type    string      `json:"type"`
+	Version int         `json:"version"`
+	Payload interface{} `json:"payload"`
+}
+
+func NewEventEnvelope(eventType string, payload interface{}) EventEnvelope {
+	return EventEnvelope{
+		Type:    eventType,
+		Version: EventVersion,
+		Payload: payload,
+	}
+}
+
+// UserSnapshot — профиль пользователя на момент события, чтобы клиенту не нужно было его запрашивать
+type UserSnapshot struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Nickname  string    `db:"nickname" json:"nickname"`
+	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
+}
+
+type MessageEventPayload struct {
+	Message Message      `json:"message"`
+	Sender  UserSnapshot `json:"sender"`
+}
+
+type MessageDeletedEventPayload struct {
+	Tombstone MessageTombstone `json:"tombstone"`
+	Actor     UserSnapshot     `json:"actor"`
+}
+
+type MessagesReadEventPayload struct {
+	ReadMark ReadMark     `json:"read_mark"`
+	Actor    UserSnapshot `json:"actor"`
+}
+
+// MemberEventPayload — участник вошёл или вышел; при исключении Actor отличается от Member
+type MemberEventPayload struct {
+	StreamID uuid.UUID    `json:"stream_id"`
+	Member   UserSnapshot `json:"member"`
+	Actor    UserSnapshot `json:"actor"`
+}
+
+type MemberRoleChangedEventPayload struct {
+	StreamID uuid.UUID    `json:"stream_id"`
+	Member   UserSnapshot `json:"member"`
+	Role     string       `json:"role"`
+	Actor    UserSnapshot `json:"actor"`
+}
+
+type StreamEditedEventPayload struct {
+	StreamID uuid.UUID      `json:"stream_id"`
+	Metadata StreamMetadata `json:"metadata"`
+	Actor    UserSnapshot   `json:"actor"`
+}
+
+type StreamRemovedEventPayload struct {
+	StreamID uuid.UUID    `json:"stream_id"`
+	Actor    UserSnapshot `json:"actor"`
+}
+
+type StreamAddedEventPayload struct {
+	StreamID   uuid.UUID    `json:"stream_id"`
+	StreamType string       `json:"stream_type"`
+	Role       string       `json:"role"`
+	Actor      UserSnapshot `json:"actor"`
+}
+
+// StreamUpdatedEventPayload обновляет счётчик непрочитанных и позицию стрима в списке
+type StreamUpdatedEventPayload struct {
+	StreamID      uuid.UUID  `json:"stream_id"`
+	UnreadCount   int64      `json:"unread_count"`
+	LastMessageID *uuid.UUID `json:"last_message_id,omitempty"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+}
+
+type UserTypingEventPayload struct {
+	StreamID  uuid.UUID `json:"stream_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,221 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// тесты фиксируют формат событий, который читают клиенты: любое изменение
+// здесь должно сопровождаться увеличением EventVersion
+func TestEventEnvelope_WireFormat(t *testing.T) {
+	t.Parallel()
+
+	streamID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	messageID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	userID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	rootID := uuid.MustParse("44444444-4444-4444-4444-444444444444")
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	user := UserSnapshot{
+		ID:        userID,
+		Nickname:  "alice",
+		AvatarURL: "https://example.com/alice.png",
+	}
+	userJSON := `{"id":"33333333-3333-3333-3333-333333333333","nickname":"alice","avatar_url":"https://example.com/alice.png"}`
+
+	tests := []struct {
+		name     string
+		envelope EventEnvelope
+		expected string
+	}{
+		{
+			name: "message_created",
+			envelope: NewEventEnvelope(MessageCreatedEvent, MessageEventPayload{
+				Message: Message{
+					ID:       messageID,
+					StreamID: streamID,
+					SenderID: userID,
+					Type:     ImageMessageType,
+					Content:  "caption",
+					Media: &MessageMedia{
+						StorageKey: "attachments/1",
+						MimeType:   "image/png",
+						Size:       10,
+						Width:      4,
+						Height:     3,
+					},
+					RootID: &rootID,
+					SentAt: at,
+				},
+				Sender: user,
+			}),
+			expected: `{"type":"message.created","version":1,"payload":{"message":{` +
+				`"id":"22222222-2222-2222-2222-222222222222",` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"sender_id":"33333333-3333-3333-3333-333333333333",` +
+				`"type":"image","content":"caption",` +
+				`"media":{"storage_key":"attachments/1","mime_type":"image/png","size":10,"width":4,"height":3},` +
+				`"root_id":"44444444-4444-4444-4444-444444444444",` +
+				`"sent_at":"2025-01-02T03:04:05Z"},` +
+				`"sender":` + userJSON + `}}`,
+		},
+		{
+			name: "message_edited",
+			envelope: NewEventEnvelope(MessageEditedEvent, MessageEventPayload{
+				Message: Message{
+					ID:        messageID,
+					StreamID:  streamID,
+					SenderID:  userID,
+					Type:      TextMessageType,
+					Content:   "edited",
+					SentAt:    at,
+					UpdatedAt: &at,
+				},
+				Sender: user,
+			}),
+			expected: `{"type":"message.edited","version":1,"payload":{"message":{` +
+				`"id":"22222222-2222-2222-2222-222222222222",` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"sender_id":"33333333-3333-3333-3333-333333333333",` +
+				`"type":"text","content":"edited",` +
+				`"sent_at":"2025-01-02T03:04:05Z","updated_at":"2025-01-02T03:04:05Z"},` +
+				`"sender":` + userJSON + `}}`,
+		},
+		{
+			name: "message_deleted",
+			envelope: NewEventEnvelope(MessageDeletedEvent, MessageDeletedEventPayload{
+				Tombstone: MessageTombstone{
+					ID:        messageID,
+					StreamID:  streamID,
+					DeletedAt: at,
+					DeletedBy: userID,
+				},
+				Actor: user,
+			}),
+			expected: `{"type":"message.deleted","version":1,"payload":{"tombstone":{` +
+				`"id":"22222222-2222-2222-2222-222222222222",` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"deleted_at":"2025-01-02T03:04:05Z",` +
+				`"deleted_by":"33333333-3333-3333-3333-333333333333"},` +
+				`"actor":` + userJSON + `}}`,
+		},
+		{
+			name: "messages_read",
+			envelope: NewEventEnvelope(MessagesReadEvent, MessagesReadEventPayload{
+				ReadMark: ReadMark{
+					StreamID:  streamID,
+					UserID:    userID,
+					MessageID: messageID,
+					SentAt:    at,
+					ReadAt:    at,
+				},
+				Actor: user,
+			}),
+			expected: `{"type":"messages.read","version":1,"payload":{"read_mark":{` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"user_id":"33333333-3333-3333-3333-333333333333",` +
+				`"message_id":"22222222-2222-2222-2222-222222222222",` +
+				`"read_at":"2025-01-02T03:04:05Z"},` +
+				`"actor":` + userJSON + `}}`,
+		},
+		{
+			name: "stream_added",
+			envelope: NewEventEnvelope(StreamAddedEvent, StreamAddedEventPayload{
+				StreamID:   streamID,
+				StreamType: GroupStreamType,
+				Role:       MemberRole,
+				Actor:      user,
+			}),
+			expected: `{"type":"stream.added","version":1,"payload":{` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"stream_type":"group","role":"member",` +
+				`"actor":` + userJSON + `}}`,
+		},
+		{
+			name: "stream_updated",
+			envelope: NewEventEnvelope(StreamUpdatedEvent, StreamUpdatedEventPayload{
+				StreamID:      streamID,
+				UnreadCount:   3,
+				LastMessageID: &messageID,
+				LastMessageAt: &at,
+			}),
+			expected: `{"type":"stream.updated","version":1,"payload":{` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"unread_count":3,` +
+				`"last_message_id":"22222222-2222-2222-2222-222222222222",` +
+				`"last_message_at":"2025-01-02T03:04:05Z"}}`,
+		},
+		{
+			name: "member_left",
+			envelope: NewEventEnvelope(MemberLeftEvent, MemberEventPayload{
+				StreamID: streamID,
+				Member:   user,
+				Actor:    user,
+			}),
+			expected: `{"type":"member.left","version":1,"payload":{` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"member":` + userJSON + `,` +
+				`"actor":` + userJSON + `}}`,
+		},
+		{
+			name: "member_role_changed",
+			envelope: NewEventEnvelope(MemberRoleChangedEvent, MemberRoleChangedEventPayload{
+				StreamID: streamID,
+				Member:   user,
+				Role:     AdminRole,
+				Actor:    user,
+			}),
+			expected: `{"type":"member.role_changed","version":1,"payload":{` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"member":` + userJSON + `,"role":"admin",` +
+				`"actor":` + userJSON + `}}`,
+		},
+		{
+			name: "stream_edited",
+			envelope: NewEventEnvelope(StreamEditedEvent, StreamEditedEventPayload{
+				StreamID: streamID,
+				Metadata: StreamMetadata{Title: "team"},
+				Actor:    user,
+			}),
+			expected: `{"type":"stream.edited","version":1,"payload":{` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"metadata":{"title":"team"},` +
+				`"actor":` + userJSON + `}}`,
+		},
+		{
+			name: "stream_removed",
+			envelope: NewEventEnvelope(StreamRemovedEvent, StreamRemovedEventPayload{
+				StreamID: streamID,
+				Actor:    user,
+			}),
+			expected: `{"type":"stream.removed","version":1,"payload":{` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"actor":` + userJSON + `}}`,
+		},
+		{
+			name: "user_typing",
+			envelope: NewEventEnvelope(UserTypingEvent, UserTypingEventPayload{
+				StreamID:  streamID,
+				UserID:    userID,
+				ExpiresAt: at,
+			}),
+			expected: `{"type":"user.typing","version":1,"payload":{` +
+				`"stream_id":"11111111-1111-1111-1111-111111111111",` +
+				`"user_id":"33333333-3333-3333-3333-333333333333",` +
+				`"expires_at":"2025-01-02T03:04:05Z"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.envelope)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(data))
+		})
+	}
+}
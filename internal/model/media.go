@@ -0,0 +1,50 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MessageMedia struct {
+	StorageKey   string  `json:"storage_key"`
+	MimeType     string  `json:"mime_type"`
+	Size         int64   `json:"size"`
+	Width        int32   `json:"width,omitempty"`
+	Height       int32   `json:"height,omitempty"`
+	DurationMs   int64   `json:"duration_ms,omitempty"`
+	Waveform     []int32 `json:"waveform,omitempty"`
+	ThumbnailKey string  `json:"thumbnail_key,omitempty"`
+}
+
+func (m MessageMedia) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+func (m *MessageMedia) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported media type: %T", src)
+	}
+
+	return json.Unmarshal(data, m)
+}
+
+type Attachment struct {
+	StorageKey   string    `db:"storage_key"`
+	UploaderID   uuid.UUID `db:"uploader_id"`
+	MimeType     string    `db:"mime_type"`
+	Size         int64     `db:"size"`
+	Width        *int32    `db:"width"`
+	Height       *int32    `db:"height"`
+	ThumbnailKey *string   `db:"thumbnail_key"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -6,16 +6,42 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	SelfDeleteFormat = "self"
+	AllDeleteFormat  = "all"
+)
+
 type MessageList []Message
 
 type Message struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	StreamID  uuid.UUID  `db:"stream_id" json:"stream_id"`
-	SenderID  uuid.UUID  `db:"sender_id" json:"sender_id"`
-	Type      string     `db:"type" json:"type"`
-	Content   string     `db:"content" json:"content"`
-	RootID    *uuid.UUID `db:"root_id" json:"root_id,omitempty"`
-	ParentID  *uuid.UUID `db:"parent_id" json:"parent_id,omitempty"`
-	SentAt    time.Time  `db:"sent_at" json:"sent_at"`
-	UpdatedAt *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+	ID        uuid.UUID     `db:"id" json:"id"`
+	StreamID  uuid.UUID     `db:"stream_id" json:"stream_id"`
+	SenderID  uuid.UUID     `db:"sender_id" json:"sender_id"`
+	Type      string        `db:"type" json:"type"`
+	Content   string        `db:"content" json:"content"`
+	Media     *MessageMedia `db:"media" json:"media,omitempty"`
+	RootID    *uuid.UUID    `db:"root_id" json:"root_id,omitempty"`
+	ParentID  *uuid.UUID    `db:"parent_id" json:"parent_id,omitempty"`
+	SentAt    time.Time     `db:"sent_at" json:"sent_at"`
+	UpdatedAt *time.Time    `db:"updated_at" json:"updated_at,omitempty"`
+	DeletedAt *time.Time    `db:"deleted_at" json:"deleted_at,omitempty"`
+
+	ReplyCount int64 `db:"reply_count" json:"reply_count,omitempty"`
+}
+
+type MessageTombstone struct {
+	ID        uuid.UUID `json:"id"`
+	StreamID  uuid.UUID `json:"stream_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy uuid.UUID `json:"deleted_by"`
+}
+
+type ReadMarkList []ReadMark
+
+type ReadMark struct {
+	StreamID  uuid.UUID `db:"stream_id" json:"stream_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	MessageID uuid.UUID `db:"message_id" json:"message_id"`
+	SentAt    time.Time `db:"sent_at" json:"-"`
+	ReadAt    time.Time `db:"read_at" json:"read_at"`
 }
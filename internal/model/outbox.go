@@ -0,0 +1,35 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OutboxEntry struct {
+	ID        int64           `db:"id"`
+	Channel   string          `db:"channel"`
+	Payload   json.RawMessage `db:"payload"`
+	Attempts  int             `db:"attempts"`
+	CreatedAt time.Time       `db:"created_at"`
+}
+
+// Event — запись журнала событий; Type берётся из конверта события
+type Event struct {
+	ID      int64           `db:"id"`
+	Xid     uint64          `db:"xid"`
+	Channel string          `db:"channel"`
+	Type    string          `db:"type"`
+	Payload json.RawMessage `db:"payload"`
+}
+
+func (e Event) Cursor() EventCursor {
+	return EventCursor{
+		Xid: e.Xid,
+		ID:  e.ID,
+	}
+}
+
+type OutboxStats struct {
+	Pending    int64   `db:"pending"`
+	LagSeconds float64 `db:"lag_seconds"`
+}
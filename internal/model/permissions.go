@@ -0,0 +1,58 @@
+package model
+
+// roleRank упорядочивает роли: управлять можно только участниками с рангом ниже своего
+var roleRank = map[string]int{
+	ReadOnlyRole: 1,
+	MemberRole:   2,
+	AdminRole:    3,
+	OwnerRole:    4,
+}
+
+func IsValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+func (m *StreamMembership) isAdmin() bool {
+	return m.Role == OwnerRole || m.Role == AdminRole
+}
+
+// CanPost — read_only не пишет никуда, в каналы пишут только owner и admin
+func (m *StreamMembership) CanPost() bool {
+	if m.Role == ReadOnlyRole {
+		return false
+	}
+
+	if m.StreamType == ChannelStreamType {
+		return m.isAdmin()
+	}
+
+	return true
+}
+
+// CanInvite — приглашать можно только в группы; в каналы и комментарии вступают сами
+func (m *StreamMembership) CanInvite() bool {
+	return m.StreamType == GroupStreamType && m.isAdmin()
+}
+
+func (m *StreamMembership) CanEditStream() bool {
+	return (m.StreamType == GroupStreamType || m.StreamType == ChannelStreamType) && m.isAdmin()
+}
+
+func (m *StreamMembership) CanDeleteOthersMessages() bool {
+	return (m.StreamType == GroupStreamType || m.StreamType == ChannelStreamType) && m.isAdmin()
+}
+
+// CanManageMember — исключать участника и менять ему роль может owner или admin с рангом выше, чем у участника
+func (m *StreamMembership) CanManageMember(target *StreamMembership) bool {
+	if m.StreamType != GroupStreamType && m.StreamType != ChannelStreamType {
+		return false
+	}
+
+	return m.isAdmin() && roleRank[m.Role] > roleRank[target.Role]
+}
+
+// CanAssignRole — назначить можно только роль ниже своей; owner передаётся отдельно
+func (m *StreamMembership) CanAssignRole(role string) bool {
+	return m.isAdmin() && roleRank[m.Role] > roleRank[role]
+}
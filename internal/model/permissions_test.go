@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamMembership_Permissions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		membership StreamMembership
+		canPost    bool
+		canInvite  bool
+		canEdit    bool
+		canDelete  bool
+	}{
+		{
+			name:       "group_member",
+			membership: StreamMembership{StreamType: GroupStreamType, Role: MemberRole},
+			canPost:    true,
+		},
+		{
+			name:       "group_read_only",
+			membership: StreamMembership{StreamType: GroupStreamType, Role: ReadOnlyRole},
+		},
+		{
+			name:       "group_admin",
+			membership: StreamMembership{StreamType: GroupStreamType, Role: AdminRole},
+			canPost:    true,
+			canInvite:  true,
+			canEdit:    true,
+			canDelete:  true,
+		},
+		{
+			name:       "channel_member",
+			membership: StreamMembership{StreamType: ChannelStreamType, Role: MemberRole},
+		},
+		{
+			name:       "channel_owner",
+			membership: StreamMembership{StreamType: ChannelStreamType, Role: OwnerRole},
+			canPost:    true,
+			canEdit:    true,
+			canDelete:  true,
+		},
+		{
+			name:       "private_owner",
+			membership: StreamMembership{StreamType: PrivateStreamType, Role: OwnerRole},
+			canPost:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.canPost, tt.membership.CanPost())
+			assert.Equal(t, tt.canInvite, tt.membership.CanInvite())
+			assert.Equal(t, tt.canEdit, tt.membership.CanEditStream())
+			assert.Equal(t, tt.canDelete, tt.membership.CanDeleteOthersMessages())
+		})
+	}
+}
+
+func TestStreamMembership_CanManageMember(t *testing.T) {
+	t.Parallel()
+
+	owner := &StreamMembership{StreamType: GroupStreamType, Role: OwnerRole}
+	admin := &StreamMembership{StreamType: GroupStreamType, Role: AdminRole}
+	member := &StreamMembership{StreamType: GroupStreamType, Role: MemberRole}
+
+	assert.True(t, owner.CanManageMember(admin))
+	assert.True(t, admin.CanManageMember(member))
+	assert.False(t, admin.CanManageMember(admin))
+	assert.False(t, admin.CanManageMember(owner))
+	assert.False(t, member.CanManageMember(&StreamMembership{Role: ReadOnlyRole}))
+
+	assert.True(t, owner.CanAssignRole(AdminRole))
+	assert.False(t, admin.CanAssignRole(AdminRole))
+	assert.True(t, admin.CanAssignRole(ReadOnlyRole))
+	assert.False(t, owner.CanAssignRole(OwnerRole))
+}
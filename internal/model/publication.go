@@ -0,0 +1,42 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChannelPublication — одна публикация пакетной отправки
+type ChannelPublication struct {
+	Channel string
+	Data    interface{}
+}
+
+type ChannelPublishError struct {
+	// Index — позиция публикации в пакете; для broadcast — позиция канала
+	Index   int
+	Channel string
+	Err     error
+}
+
+// BatchPublishError возвращается, когда часть публикаций пакета не доставлена; остальные доставлены
+type BatchPublishError struct {
+	Failed []ChannelPublishError
+}
+
+func (e *BatchPublishError) Error() string {
+	parts := make([]string, 0, len(e.Failed))
+	for _, failed := range e.Failed {
+		parts = append(parts, fmt.Sprintf("%s: %v", failed.Channel, failed.Err))
+	}
+
+	return fmt.Sprintf("failed to publish to %d channels: %s", len(e.Failed), strings.Join(parts, "; "))
+}
+
+// JoinPublishErrors возвращает nil, если все публикации пакета доставлены
+func JoinPublishErrors(failed []ChannelPublishError) error {
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return &BatchPublishError{Failed: failed}
+}
@@ -1,21 +1,104 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
 const (
 	PrivateStreamType = "private"
+	GroupStreamType   = "group"
+	ChannelStreamType = "channel"
+	CommentStreamType = "comment"
 
-	TextMessageType = "text"
+	TextMessageType   = "text"
+	ImageMessageType  = "image"
+	VideoMessageType  = "video"
+	FileMessageType   = "file"
+	SpeechMessageType = "speech"
+	CircleMessageType = "circle"
 )
 
+type StreamMetadata struct {
+	Title     string `json:"title"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+func (m *StreamMetadata) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported metadata type: %T", src)
+	}
+
+	return json.Unmarshal(data, m)
+}
+
 type PrivateStreamPreviewList []PrivateStreamPreview
 
 type PrivateStreamPreview struct {
+	StreamID             string     `db:"stream_id"`
+	LastMessageContent   *string    `db:"last_message_content"`
+	StreamName           string     `db:"stream_name"`
+	AvatarURL            string     `db:"avatar_url"`
+	CompanionID          string     `db:"companion_id"`
+	CompanionLastOnline  *time.Time `db:"companion_last_online"`
+	LastMessageTimestamp *time.Time `db:"last_message_timestamp"`
+	UnreadCount          int64      `db:"unread_count"`
+	Muted                bool       `db:"muted"`
+	NotifyLevel          string     `db:"notify_level"`
+}
+
+type GroupStreamPreviewList []GroupStreamPreview
+
+type GroupStreamPreview struct {
+	StreamID             string     `db:"stream_id"`
+	LastMessageContent   *string    `db:"last_message_content"`
+	StreamName           string     `db:"stream_name"`
+	AvatarURL            string     `db:"avatar_url"`
+	LastMessageTimestamp *time.Time `db:"last_message_timestamp"`
+	UnreadCount          int64      `db:"unread_count"`
+	Muted                bool       `db:"muted"`
+	NotifyLevel          string     `db:"notify_level"`
+}
+
+type ChannelStreamPreviewList []ChannelStreamPreview
+
+type ChannelStreamPreview struct {
 	StreamID             string     `db:"stream_id"`
 	LastMessageContent   *string    `db:"last_message_content"`
 	StreamName           string     `db:"stream_name"`
 	AvatarURL            string     `db:"avatar_url"`
 	LastMessageTimestamp *time.Time `db:"last_message_timestamp"`
+	UnreadCount          int64      `db:"unread_count"`
+	Role                 string     `db:"role"`
+	Muted                bool       `db:"muted"`
+	NotifyLevel          string     `db:"notify_level"`
+}
+
+type StreamPreviewList []StreamPreview
+
+// StreamPreview — строка общего списка стримов; поля companion_* заполнены только для личных стримов
+type StreamPreview struct {
+	StreamID                  string     `db:"stream_id"`
+	StreamType                string     `db:"stream_type"`
+	StreamName                string     `db:"stream_name"`
+	AvatarURL                 string     `db:"avatar_url"`
+	Role                      string     `db:"role"`
+	CompanionID               *string    `db:"companion_id"`
+	LastMessageID             *string    `db:"last_message_id"`
+	LastMessageContent        *string    `db:"last_message_content"`
+	LastMessageSenderID       *string    `db:"last_message_sender_id"`
+	LastMessageSenderNickname *string    `db:"last_message_sender_nickname"`
+	LastMessageTimestamp      *time.Time `db:"last_message_timestamp"`
+	ActivityAt                time.Time  `db:"activity_at"`
+	UnreadCount               int64      `db:"unread_count"`
+	Muted                     bool       `db:"muted"`
+	NotifyLevel               string     `db:"notify_level"`
+	MutedUntil                *time.Time `db:"muted_until"`
 }
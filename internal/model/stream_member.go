@@ -1,8 +1,53 @@
 package model
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OwnerRole    = "owner"
+	AdminRole    = "admin"
+	MemberRole   = "member"
+	ReadOnlyRole = "read_only"
+
+	AllNotifyLevel      = "all"
+	MentionsNotifyLevel = "mentions"
+	NoneNotifyLevel     = "none"
+)
+
 type StreamMember struct {
 	UserID   string
 	Metadata string
+	Role     string
+}
+
+type StreamMembership struct {
+	StreamID   string `db:"stream_id"`
+	StreamType string `db:"stream_type"`
+	Role       string `db:"role"`
+}
+
+// NotificationSettings — настройки уведомлений участника. Notify = false глушит стрим
+// навсегда или до MutedUntil; уровень действует, пока стрим не заглушён
+type NotificationSettings struct {
+	Notify     bool       `db:"notify"`
+	Level      string     `db:"notify_level"`
+	MutedUntil *time.Time `db:"muted_until"`
+}
+
+func (s NotificationSettings) Muted(now time.Time) bool {
+	return !s.Notify && (s.MutedUntil == nil || s.MutedUntil.After(now))
+}
+
+func IsValidNotifyLevel(level string) bool {
+	switch level {
+	case AllNotifyLevel, MentionsNotifyLevel, NoneNotifyLevel:
+		return true
+	default:
+		return false
+	}
 }
 
 type StreamMemberParams struct {
@@ -15,3 +60,28 @@ type UserSubscription struct {
 	UserID  string
 	Channel string
 }
+
+// PrivatePair — канонический ключ личного стрима, не зависящий от порядка пользователей
+// и от формы записи их UUID
+func PrivatePair(userID, companionID uuid.UUID) string {
+	low, high := userID.String(), companionID.String()
+	if high < low {
+		low, high = high, low
+	}
+
+	return low + ":" + high
+}
+
+// PersonalChannelPrefix — namespace персональных каналов; "#" ограничивает канал одним пользователем в Centrifugo
+const PersonalChannelPrefix = "personal:#"
+
+func PersonalChannel(userID string) string {
+	return PersonalChannelPrefix + userID
+}
+
+// PersonalChannelMember — участник стрима с персональной подпиской и его счётчиком непрочитанных
+type PersonalChannelMember struct {
+	UserID      uuid.UUID `db:"user_id"`
+	Channel     string    `db:"channel"`
+	UnreadCount int64     `db:"unread_count"`
+}
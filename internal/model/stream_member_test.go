@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivatePair(t *testing.T) {
+	t.Parallel()
+
+	low := uuid.MustParse("0b3c1f8e-7a2d-4c55-9e61-2f4a8d9b1c01")
+	high := uuid.MustParse("{F1E2D3C4-B5A6-4789-8A0B-1C2D3E4F5A6B}")
+
+	expected := "0b3c1f8e-7a2d-4c55-9e61-2f4a8d9b1c01:f1e2d3c4-b5a6-4789-8a0b-1c2d3e4f5a6b"
+	assert.Equal(t, expected, PrivatePair(low, high))
+	assert.Equal(t, expected, PrivatePair(high, low))
+}
+
+func TestNotificationSettings_Muted(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		settings NotificationSettings
+		muted    bool
+	}{
+		{
+			name:     "notify",
+			settings: NotificationSettings{Notify: true, Level: AllNotifyLevel},
+		},
+		{
+			name:     "muted_forever",
+			settings: NotificationSettings{Notify: false, Level: AllNotifyLevel},
+			muted:    true,
+		},
+		{
+			name:     "muted_until_later",
+			settings: NotificationSettings{Notify: false, Level: MentionsNotifyLevel, MutedUntil: &later},
+			muted:    true,
+		},
+		{
+			name:     "mute_expired",
+			settings: NotificationSettings{Notify: false, Level: MentionsNotifyLevel, MutedUntil: &earlier},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.muted, tt.settings.Muted(now))
+		})
+	}
+}
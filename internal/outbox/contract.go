@@ -0,0 +1,24 @@
+//go:generate mockgen -destination=mock_contract_test.go -package=${GOPACKAGE} -source=contract.go
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+type DBRepo interface {
+	TryLockOutbox(ctx context.Context) (bool, error)
+	GetPendingOutbox(ctx context.Context, limit int) ([]model.OutboxEntry, error)
+	DeleteOutboxEntry(ctx context.Context, id int64) error
+	MarkOutboxAttemptFailed(ctx context.Context, id int64, backoff time.Duration, lastError string) error
+	GetOutboxStats(ctx context.Context) (*model.OutboxStats, error)
+	DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error)
+
+	WithTx(ctx context.Context, cb func(ctx context.Context) error) error
+}
+
+type Publisher interface {
+	PublishBatch(ctx context.Context, publications []model.ChannelPublication) error
+}
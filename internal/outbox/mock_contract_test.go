@@ -0,0 +1,176 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package outbox is a generated GoMock package.
+package outbox
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/s21platform/chat-service/internal/model"
+)
+
+// MockDBRepo is a mock of DBRepo interface.
+type MockDBRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDBRepoMockRecorder
+}
+
+// MockDBRepoMockRecorder is the mock recorder for MockDBRepo.
+type MockDBRepoMockRecorder struct {
+	mock *MockDBRepo
+}
+
+// NewMockDBRepo creates a new mock instance.
+func NewMockDBRepo(ctrl *gomock.Controller) *MockDBRepo {
+	mock := &MockDBRepo{ctrl: ctrl}
+	mock.recorder = &MockDBRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDBRepo) EXPECT() *MockDBRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteEventsBefore mocks base method.
+func (m *MockDBRepo) DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventsBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEventsBefore indicates an expected call of DeleteEventsBefore.
+func (mr *MockDBRepoMockRecorder) DeleteEventsBefore(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventsBefore", reflect.TypeOf((*MockDBRepo)(nil).DeleteEventsBefore), ctx, before)
+}
+
+// DeleteOutboxEntry mocks base method.
+func (m *MockDBRepo) DeleteOutboxEntry(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOutboxEntry", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOutboxEntry indicates an expected call of DeleteOutboxEntry.
+func (mr *MockDBRepoMockRecorder) DeleteOutboxEntry(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOutboxEntry", reflect.TypeOf((*MockDBRepo)(nil).DeleteOutboxEntry), ctx, id)
+}
+
+// GetOutboxStats mocks base method.
+func (m *MockDBRepo) GetOutboxStats(ctx context.Context) (*model.OutboxStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxStats", ctx)
+	ret0, _ := ret[0].(*model.OutboxStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxStats indicates an expected call of GetOutboxStats.
+func (mr *MockDBRepoMockRecorder) GetOutboxStats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxStats", reflect.TypeOf((*MockDBRepo)(nil).GetOutboxStats), ctx)
+}
+
+// GetPendingOutbox mocks base method.
+func (m *MockDBRepo) GetPendingOutbox(ctx context.Context, limit int) ([]model.OutboxEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingOutbox", ctx, limit)
+	ret0, _ := ret[0].([]model.OutboxEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingOutbox indicates an expected call of GetPendingOutbox.
+func (mr *MockDBRepoMockRecorder) GetPendingOutbox(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingOutbox", reflect.TypeOf((*MockDBRepo)(nil).GetPendingOutbox), ctx, limit)
+}
+
+// MarkOutboxAttemptFailed mocks base method.
+func (m *MockDBRepo) MarkOutboxAttemptFailed(ctx context.Context, id int64, backoff time.Duration, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxAttemptFailed", ctx, id, backoff, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxAttemptFailed indicates an expected call of MarkOutboxAttemptFailed.
+func (mr *MockDBRepoMockRecorder) MarkOutboxAttemptFailed(ctx, id, backoff, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxAttemptFailed", reflect.TypeOf((*MockDBRepo)(nil).MarkOutboxAttemptFailed), ctx, id, backoff, lastError)
+}
+
+// TryLockOutbox mocks base method.
+func (m *MockDBRepo) TryLockOutbox(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLockOutbox", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryLockOutbox indicates an expected call of TryLockOutbox.
+func (mr *MockDBRepoMockRecorder) TryLockOutbox(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLockOutbox", reflect.TypeOf((*MockDBRepo)(nil).TryLockOutbox), ctx)
+}
+
+// WithTx mocks base method.
+func (m *MockDBRepo) WithTx(ctx context.Context, cb func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, cb)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockDBRepoMockRecorder) WithTx(ctx, cb interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockDBRepo)(nil).WithTx), ctx, cb)
+}
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishBatch mocks base method.
+func (m *MockPublisher) PublishBatch(ctx context.Context, publications []model.ChannelPublication) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishBatch", ctx, publications)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishBatch indicates an expected call of PublishBatch.
+func (mr *MockPublisherMockRecorder) PublishBatch(ctx, publications interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishBatch", reflect.TypeOf((*MockPublisher)(nil).PublishBatch), ctx, publications)
+}
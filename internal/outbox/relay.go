@@ -0,0 +1,229 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	logger_lib "github.com/s21platform/logger-lib"
+	"github.com/s21platform/metrics-lib/pkg"
+
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+// eventsPruneInterval — как часто из журнала событий удаляются записи старше eventRetention
+const eventsPruneInterval = time.Minute
+
+type Relay struct {
+	repository     DBRepo
+	publisher      Publisher
+	pollInterval   time.Duration
+	batchSize      int
+	baseBackoff    time.Duration
+	maxBackoff     time.Duration
+	maxAttempts    int
+	eventRetention time.Duration
+}
+
+func New(repo DBRepo, publisher Publisher, cfg *config.Config) *Relay {
+	return &Relay{
+		repository:     repo,
+		publisher:      publisher,
+		pollInterval:   cfg.Outbox.PollInterval,
+		batchSize:      cfg.Outbox.BatchSize,
+		baseBackoff:    cfg.Outbox.BaseBackoff,
+		maxBackoff:     cfg.Outbox.MaxBackoff,
+		maxAttempts:    cfg.Outbox.MaxAttempts,
+		eventRetention: cfg.Outbox.EventRetention,
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("Run")
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+	for {
+		delivered, err := r.RelayBatch(ctx)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to relay outbox batch: %v", err))
+		}
+
+		r.reportLag(ctx)
+
+		if time.Since(lastPrune) >= eventsPruneInterval {
+			r.pruneEvents(ctx)
+			lastPrune = time.Now()
+		}
+
+		// пока есть что отправлять, не ждём следующего тика
+		if err == nil && delivered > 0 && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch отправляет очередную пачку записей и возвращает количество доставленных.
+// Записи одного канала отправляются строго по порядку: после первой ошибки канал
+// пропускается до истечения backoff. Записи разных каналов уходят пакетами, см. splitRounds.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("RelayBatch")
+
+	m := pkg.FromContext(ctx, config.KeyMetrics)
+
+	delivered := 0
+	err := r.repository.WithTx(ctx, func(ctx context.Context) error {
+		locked, err := r.repository.TryLockOutbox(ctx)
+		if err != nil {
+			return err
+		}
+
+		// outbox уже разбирает другой экземпляр relay
+		if !locked {
+			return nil
+		}
+
+		entries, err := r.repository.GetPendingOutbox(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		blocked := make(map[string]bool)
+		for _, round := range splitRounds(entries) {
+			pending := make([]model.OutboxEntry, 0, len(round))
+			publications := make([]model.ChannelPublication, 0, len(round))
+			for _, entry := range round {
+				if blocked[entry.Channel] {
+					continue
+				}
+
+				pending = append(pending, entry)
+				publications = append(publications, model.ChannelPublication{
+					Channel: entry.Channel,
+					Data:    entry.Payload,
+				})
+			}
+
+			if len(pending) == 0 {
+				continue
+			}
+
+			failures := make(map[int]error)
+			publishErr := r.publisher.PublishBatch(ctx, publications)
+
+			var batchErr *model.BatchPublishError
+			switch {
+			case publishErr == nil:
+			case errors.As(publishErr, &batchErr):
+				for _, failed := range batchErr.Failed {
+					failures[failed.Index] = failed.Err
+				}
+			default:
+				// запрос не выполнен целиком — не доставлена ни одна запись раунда
+				for i := range pending {
+					failures[i] = publishErr
+				}
+			}
+
+			for i, entry := range pending {
+				entryErr, failed := failures[i]
+				if !failed {
+					if err := r.repository.DeleteOutboxEntry(ctx, entry.ID); err != nil {
+						return err
+					}
+					delivered++
+					m.Increment("outbox.delivered")
+					continue
+				}
+
+				blocked[entry.Channel] = true
+				m.Increment("outbox.publish_error")
+
+				attempts := entry.Attempts + 1
+				if attempts >= r.maxAttempts {
+					logger.Error(fmt.Sprintf("dropping outbox entry %d for channel %s after %d attempts: %v", entry.ID, entry.Channel, attempts, entryErr))
+					if err := r.repository.DeleteOutboxEntry(ctx, entry.ID); err != nil {
+						return err
+					}
+					m.Increment("outbox.dropped")
+					continue
+				}
+
+				logger.Error(fmt.Sprintf("failed to publish outbox entry %d to channel %s: %v", entry.ID, entry.Channel, entryErr))
+				if err := r.repository.MarkOutboxAttemptFailed(ctx, entry.ID, r.backoff(attempts), entryErr.Error()); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return delivered, err
+}
+
+func (r *Relay) reportLag(ctx context.Context) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	m := pkg.FromContext(ctx, config.KeyMetrics)
+
+	stats, err := r.repository.GetOutboxStats(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get outbox stats: %v", err))
+		return
+	}
+
+	m.Gauge("outbox.pending", float64(stats.Pending))
+	m.Gauge("outbox.lag_seconds", stats.LagSeconds)
+}
+
+func (r *Relay) pruneEvents(ctx context.Context) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	m := pkg.FromContext(ctx, config.KeyMetrics)
+
+	deleted, err := r.repository.DeleteEventsBefore(ctx, time.Now().Add(-r.eventRetention))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to prune events: %v", err))
+		return
+	}
+
+	m.Count("events.pruned", deleted)
+}
+
+// splitRounds раскладывает записи по раундам: i-й раунд содержит i-ю запись каждого канала,
+// поэтому раунд можно отправить одним пакетом, не нарушая порядок внутри канала
+func splitRounds(entries []model.OutboxEntry) [][]model.OutboxEntry {
+	var rounds [][]model.OutboxEntry
+	depth := make(map[string]int)
+	for _, entry := range entries {
+		round := depth[entry.Channel]
+		depth[entry.Channel]++
+
+		if round == len(rounds) {
+			rounds = append(rounds, nil)
+		}
+		rounds[round] = append(rounds[round], entry)
+	}
+
+	return rounds
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.baseBackoff
+	for i := 1; i < attempts && backoff < r.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, r.maxBackoff)
+}
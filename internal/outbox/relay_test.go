@@ -0,0 +1,184 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logger_lib "github.com/s21platform/logger-lib"
+	"github.com/s21platform/metrics-lib/pkg"
+
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+func newTestRelay(repo DBRepo, publisher Publisher) *Relay {
+	return New(repo, publisher, &config.Config{
+		Outbox: config.Outbox{
+			PollInterval: time.Millisecond,
+			BatchSize:    10,
+			BaseBackoff:  time.Second,
+			MaxBackoff:   time.Minute,
+			MaxAttempts:  3,
+		},
+	})
+}
+
+func newTestContext(ctrl *gomock.Controller) (context.Context, *logger_lib.MockLoggerInterface, *pkg.MockMetricInterface) {
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+	mockMetrics := pkg.NewMockMetricInterface(ctrl)
+
+	ctx := context.WithValue(context.Background(), config.KeyLogger, mockLogger)
+	ctx = context.WithValue(ctx, config.KeyMetrics, mockMetrics)
+
+	return ctx, mockLogger, mockMetrics
+}
+
+func TestRelay_RelayBatch(t *testing.T) {
+	t.Parallel()
+
+	payload := json.RawMessage(`{"event":"edited"}`)
+
+	t.Run("keeps_channel_order_after_failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockPublisher := NewMockPublisher(ctrl)
+		ctx, mockLogger, mockMetrics := newTestContext(ctrl)
+
+		mockLogger.EXPECT().AddFuncName("RelayBatch")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().TryLockOutbox(gomock.Any()).Return(true, nil)
+		mockRepo.EXPECT().GetPendingOutbox(gomock.Any(), 10).Return([]model.OutboxEntry{
+			{ID: 1, Channel: "a", Payload: payload, Attempts: 1},
+			{ID: 2, Channel: "b", Payload: payload},
+			{ID: 3, Channel: "a", Payload: payload},
+		}, nil)
+
+		mockPublisher.EXPECT().PublishBatch(gomock.Any(), []model.ChannelPublication{
+			{Channel: "a", Data: payload},
+			{Channel: "b", Data: payload},
+		}).Return(&model.BatchPublishError{Failed: []model.ChannelPublishError{
+			{Index: 0, Channel: "a", Err: errors.New("centrifugo is down")},
+		}})
+		mockRepo.EXPECT().MarkOutboxAttemptFailed(gomock.Any(), int64(1), 2*time.Second, "centrifugo is down").Return(nil)
+		mockRepo.EXPECT().DeleteOutboxEntry(gomock.Any(), int64(2)).Return(nil)
+		mockMetrics.EXPECT().Increment("outbox.publish_error")
+		mockMetrics.EXPECT().Increment("outbox.delivered")
+
+		relay := newTestRelay(mockRepo, mockPublisher)
+		delivered, err := relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+	})
+
+	t.Run("batches_channels_by_rounds", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockPublisher := NewMockPublisher(ctrl)
+		ctx, mockLogger, mockMetrics := newTestContext(ctrl)
+
+		mockLogger.EXPECT().AddFuncName("RelayBatch")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().TryLockOutbox(gomock.Any()).Return(true, nil)
+		mockRepo.EXPECT().GetPendingOutbox(gomock.Any(), 10).Return([]model.OutboxEntry{
+			{ID: 1, Channel: "a", Payload: payload},
+			{ID: 2, Channel: "a", Payload: payload},
+			{ID: 3, Channel: "b", Payload: payload},
+			{ID: 4, Channel: "c", Payload: payload},
+		}, nil)
+
+		gomock.InOrder(
+			mockPublisher.EXPECT().PublishBatch(gomock.Any(), []model.ChannelPublication{
+				{Channel: "a", Data: payload},
+				{Channel: "b", Data: payload},
+				{Channel: "c", Data: payload},
+			}).Return(nil),
+			mockPublisher.EXPECT().PublishBatch(gomock.Any(), []model.ChannelPublication{
+				{Channel: "a", Data: payload},
+			}).Return(nil),
+		)
+		for _, id := range []int64{1, 3, 4, 2} {
+			mockRepo.EXPECT().DeleteOutboxEntry(gomock.Any(), id).Return(nil)
+		}
+		mockMetrics.EXPECT().Increment("outbox.delivered").Times(4)
+
+		relay := newTestRelay(mockRepo, mockPublisher)
+		delivered, err := relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 4, delivered)
+	})
+
+	t.Run("drops_after_max_attempts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockPublisher := NewMockPublisher(ctrl)
+		ctx, mockLogger, mockMetrics := newTestContext(ctrl)
+
+		mockLogger.EXPECT().AddFuncName("RelayBatch")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().TryLockOutbox(gomock.Any()).Return(true, nil)
+		mockRepo.EXPECT().GetPendingOutbox(gomock.Any(), 10).Return([]model.OutboxEntry{
+			{ID: 7, Channel: "a", Payload: payload, Attempts: 2},
+		}, nil)
+		mockPublisher.EXPECT().PublishBatch(gomock.Any(), []model.ChannelPublication{
+			{Channel: "a", Data: payload},
+		}).Return(errors.New("bad payload"))
+		mockRepo.EXPECT().DeleteOutboxEntry(gomock.Any(), int64(7)).Return(nil)
+		mockMetrics.EXPECT().Increment("outbox.publish_error")
+		mockMetrics.EXPECT().Increment("outbox.dropped")
+
+		relay := newTestRelay(mockRepo, mockPublisher)
+		delivered, err := relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("locked_by_another_relay", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		ctx, mockLogger, _ := newTestContext(ctrl)
+
+		mockLogger.EXPECT().AddFuncName("RelayBatch")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().TryLockOutbox(gomock.Any()).Return(false, nil)
+
+		relay := newTestRelay(mockRepo, nil)
+		delivered, err := relay.RelayBatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+}
+
+func TestRelay_backoff(t *testing.T) {
+	t.Parallel()
+
+	relay := newTestRelay(nil, nil)
+
+	assert.Equal(t, time.Second, relay.backoff(1))
+	assert.Equal(t, 4*time.Second, relay.backoff(3))
+	assert.Equal(t, time.Minute, relay.backoff(30))
+}
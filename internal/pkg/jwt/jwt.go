@@ -19,7 +19,7 @@ func New(secret string) *Generator {
 	}
 }
 
-func (g *Generator) GenerateConnectToken(userID string) (string, int64, error) {
+func (g *Generator) GenerateConnectToken(userID string, channels []string) (string, int64, error) {
 	now := time.Now()
 	expiresAt := now.Add(30 * time.Minute)
 
@@ -29,6 +29,7 @@ func (g *Generator) GenerateConnectToken(userID string) (string, int64, error) {
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
+		Channels: channels,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
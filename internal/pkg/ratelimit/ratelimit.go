@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter пропускает не больше одного события на ключ за interval.
+// Состояние хранится в памяти процесса, поэтому на каждой реплике лимит свой.
+type Limiter struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	last      map[string]time.Time
+	lastSweep time.Time
+}
+
+func New(interval time.Duration) *Limiter {
+	return &Limiter{
+		interval: interval,
+		now:      time.Now,
+		last:     make(map[string]time.Time),
+	}
+}
+
+// Allow отмечает событие по ключу; если оно не пропущено, возвращает время до следующей попытки
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l.interval <= 0 {
+		return true, 0
+	}
+
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	if last, ok := l.last[key]; ok {
+		if elapsed := now.Sub(last); elapsed < l.interval {
+			return false, l.interval - elapsed
+		}
+	}
+
+	l.last[key] = now
+	return true, 0
+}
+
+// sweep раз в interval удаляет ключи, лимит которых уже истёк
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.interval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, last := range l.last {
+		if now.Sub(last) >= l.interval {
+			delete(l.last, key)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("one_event_per_interval", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		l := New(3 * time.Second)
+		l.now = func() time.Time { return now }
+
+		allowed, _ := l.Allow("user:stream")
+		assert.True(t, allowed)
+
+		now = now.Add(time.Second)
+		allowed, retryAfter := l.Allow("user:stream")
+		assert.False(t, allowed)
+		assert.Equal(t, 2*time.Second, retryAfter)
+
+		allowed, _ = l.Allow("user:other")
+		assert.True(t, allowed)
+
+		now = now.Add(2 * time.Second)
+		allowed, _ = l.Allow("user:stream")
+		assert.True(t, allowed)
+	})
+
+	t.Run("sweeps_expired_keys", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		l := New(time.Second)
+		l.now = func() time.Time { return now }
+
+		l.Allow("a")
+		l.Allow("b")
+
+		now = now.Add(2 * time.Second)
+		l.Allow("c")
+
+		assert.Len(t, l.last, 1)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		l := New(0)
+
+		for i := 0; i < 3; i++ {
+			allowed, _ := l.Allow("key")
+			assert.True(t, allowed)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package thumbnail
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // регистрация декодера gif
+	"image/jpeg"
+	_ "image/png" // регистрация декодера png
+)
+
+const (
+	MimeType = "image/jpeg"
+
+	// защита от декомпрессионных бомб
+	maxSourcePixels = 50_000_000
+	jpegQuality     = 80
+)
+
+type Thumbnail struct {
+	Data []byte
+	// размеры исходного изображения
+	Width  int32
+	Height int32
+}
+
+// Generate уменьшает изображение так, чтобы большая сторона не превышала maxSide,
+// и кодирует результат в JPEG. Прозрачные области заливаются белым.
+func Generate(data []byte, maxSide int) (*Thumbnail, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image config: %w", err)
+	}
+
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxSourcePixels {
+		return nil, fmt.Errorf("unsupported image dimensions %dx%d", cfg.Width, cfg.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := fit(width, height, maxSide)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		y0 := bounds.Min.Y + y*height/dstHeight
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*height/dstHeight)
+		for x := 0; x < dstWidth; x++ {
+			x0 := bounds.Min.X + x*width/dstWidth
+			x1 := max(x0+1, bounds.Min.X+(x+1)*width/dstWidth)
+			dst.SetRGBA(x, y, average(src, x0, y0, x1, y1))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+
+	return &Thumbnail{
+		Data:   buf.Bytes(),
+		Width:  int32(width),
+		Height: int32(height),
+	}, nil
+}
+
+func fit(width, height, maxSide int) (int, int) {
+	if maxSide <= 0 || (width <= maxSide && height <= maxSide) {
+		return width, height
+	}
+
+	if width >= height {
+		return maxSide, max(1, height*maxSide/width)
+	}
+
+	return max(1, width*maxSide/height), maxSide
+}
+
+func average(src image.Image, x0, y0, x1, y1 int) color.RGBA {
+	var r, g, b, a, n uint64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			cr, cg, cb, ca := src.At(x, y).RGBA()
+			r += uint64(cr)
+			g += uint64(cg)
+			b += uint64(cb)
+			a += uint64(ca)
+			n++
+		}
+	}
+
+	// цвета premultiplied, поэтому белый фон добавляется как (1 - alpha)
+	white := n*0xffff - a
+	return color.RGBA{
+		R: uint8((r + white) / n >> 8),
+		G: uint8((g + white) / n >> 8),
+		B: uint8((b + white) / n >> 8),
+		A: 0xff,
+	}
+}
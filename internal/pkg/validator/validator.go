@@ -4,10 +4,21 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/google/uuid"
+
 	api "github.com/s21platform/chat-service/internal/generated"
 	"github.com/s21platform/chat-service/internal/model"
 )
 
+const (
+	maxGroupParticipants = 200
+	maxStreamTitleLength = 100
+	maxContentLength     = 500
+	maxWaveformLength    = 256
+	maxSpeechDurationMs  = 60 * 60 * 1000
+	maxCircleDurationMs  = 60 * 1000
+)
+
 type Validator struct{}
 
 func New() *Validator {
@@ -33,6 +44,23 @@ func (v *Validator) ValidateCreateStream(req *api.CreateStreamRequest, creatorID
 		if totalParticipants != 2 {
 			return fmt.Errorf("private stream requires exactly 2 participants, got %d", totalParticipants)
 		}
+	case model.GroupStreamType:
+		if err := validateStreamTitle(req.Title); err != nil {
+			return err
+		}
+		if totalParticipants < 2 {
+			return fmt.Errorf("group stream requires at least 2 participants, got %d", totalParticipants)
+		}
+		if totalParticipants > maxGroupParticipants {
+			return fmt.Errorf("group stream allows at most %d participants, got %d", maxGroupParticipants, totalParticipants)
+		}
+	case model.ChannelStreamType:
+		if err := validateStreamTitle(req.Title); err != nil {
+			return err
+		}
+		if totalParticipants > maxGroupParticipants {
+			return fmt.Errorf("channel stream allows at most %d initial participants, got %d", maxGroupParticipants, totalParticipants)
+		}
 	default:
 		return fmt.Errorf("stream type '%s' is not supported", req.Type)
 	}
@@ -40,21 +68,168 @@ func (v *Validator) ValidateCreateStream(req *api.CreateStreamRequest, creatorID
 	return nil
 }
 
-func (v *Validator) ValidateSendMessage(req *api.SendMessageRequest) error {
-	if strings.TrimSpace(req.Content) == "" {
-		return fmt.Errorf("content cannot be empty")
+func (v *Validator) ValidateUpdateStream(req *api.UpdateStreamRequest) error {
+	if req.Title == nil && req.AvatarUrl == nil {
+		return fmt.Errorf("nothing to update")
+	}
+
+	if req.Title != nil {
+		return validateStreamTitle(req.Title)
+	}
+
+	return nil
+}
+
+func validateStreamTitle(title *string) error {
+	if title == nil || strings.TrimSpace(*title) == "" {
+		return fmt.Errorf("stream title is required")
+	}
+
+	if len([]rune(*title)) > maxStreamTitleLength {
+		return fmt.Errorf("stream title exceeds maximum length of %d characters", maxStreamTitleLength)
 	}
 
+	return nil
+}
+
+func (v *Validator) ValidateSendMessage(req *api.SendMessageRequest) error {
 	if strings.TrimSpace(req.MessageType) == "" {
 		return fmt.Errorf("message_type is required")
 	}
 
-	if len([]rune(req.Content)) > 500 {
-		return fmt.Errorf("content exceeds maximum length of 500 characters")
+	if len([]rune(req.Content)) > maxContentLength {
+		return fmt.Errorf("content exceeds maximum length of %d characters", maxContentLength)
+	}
+
+	switch req.MessageType {
+	case model.TextMessageType:
+		if strings.TrimSpace(req.Content) == "" {
+			return fmt.Errorf("content cannot be empty")
+		}
+		if req.Media != nil {
+			return fmt.Errorf("text message cannot contain media")
+		}
+	case model.ImageMessageType, model.VideoMessageType, model.FileMessageType,
+		model.SpeechMessageType, model.CircleMessageType:
+		if err := validateMedia(req.MessageType, req.Media); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("message type '%s' is not supported", req.MessageType)
+	}
+
+	if req.ParentId != nil && *req.ParentId != "" {
+		if _, err := uuid.Parse(*req.ParentId); err != nil {
+			return fmt.Errorf("invalid parent_id: %v", err)
+		}
+	}
+
+	if req.RootId != nil && *req.RootId != "" {
+		if _, err := uuid.Parse(*req.RootId); err != nil {
+			return fmt.Errorf("invalid root_id: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func validateMedia(messageType string, media *api.MessageMedia) error {
+	if media == nil {
+		return fmt.Errorf("%s message requires media", messageType)
+	}
+
+	if strings.TrimSpace(media.StorageKey) == "" {
+		return fmt.Errorf("media storage_key is required")
+	}
+
+	if strings.TrimSpace(media.MimeType) == "" {
+		return fmt.Errorf("media mime_type is required")
+	}
+
+	if media.Size <= 0 {
+		return fmt.Errorf("media size must be positive")
+	}
+
+	width := int32Value(media.Width)
+	height := int32Value(media.Height)
+	duration := int64Value(media.DurationMs)
+
+	switch messageType {
+	case model.ImageMessageType:
+		if !strings.HasPrefix(media.MimeType, "image/") {
+			return fmt.Errorf("image message requires an image/* mime type, got '%s'", media.MimeType)
+		}
+		if width <= 0 || height <= 0 {
+			return fmt.Errorf("image message requires positive width and height")
+		}
+	case model.VideoMessageType:
+		if !strings.HasPrefix(media.MimeType, "video/") {
+			return fmt.Errorf("video message requires a video/* mime type, got '%s'", media.MimeType)
+		}
+		if width <= 0 || height <= 0 {
+			return fmt.Errorf("video message requires positive width and height")
+		}
+		if duration <= 0 {
+			return fmt.Errorf("video message requires positive duration_ms")
+		}
+	case model.SpeechMessageType:
+		if !strings.HasPrefix(media.MimeType, "audio/") {
+			return fmt.Errorf("speech message requires an audio/* mime type, got '%s'", media.MimeType)
+		}
+		if duration <= 0 || duration > maxSpeechDurationMs {
+			return fmt.Errorf("speech message duration_ms must be between 1 and %d", maxSpeechDurationMs)
+		}
+		if media.Waveform == nil || len(*media.Waveform) == 0 {
+			return fmt.Errorf("speech message requires waveform")
+		}
+		if len(*media.Waveform) > maxWaveformLength {
+			return fmt.Errorf("waveform exceeds maximum length of %d samples", maxWaveformLength)
+		}
+		for _, sample := range *media.Waveform {
+			if sample < 0 || sample > 255 {
+				return fmt.Errorf("waveform samples must be in range 0-255")
+			}
+		}
+	case model.CircleMessageType:
+		if !strings.HasPrefix(media.MimeType, "video/") {
+			return fmt.Errorf("circle message requires a video/* mime type, got '%s'", media.MimeType)
+		}
+		if width <= 0 || width != height {
+			return fmt.Errorf("circle message requires equal positive width and height")
+		}
+		if duration <= 0 || duration > maxCircleDurationMs {
+			return fmt.Errorf("circle message duration_ms must be between 1 and %d", maxCircleDurationMs)
+		}
+	}
+
+	if messageType != model.SpeechMessageType && media.Waveform != nil {
+		return fmt.Errorf("waveform is only allowed for speech messages")
+	}
+
+	return nil
+}
+
+func int32Value(v *int32) int32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func int64Value(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func (v *Validator) ValidateEditMessage(req *api.EditMessageRequest) error {
+	if strings.TrimSpace(req.Content) == "" {
+		return fmt.Errorf("content cannot be empty")
 	}
 
-	if req.MessageType != model.TextMessageType {
-		return fmt.Errorf("message type '%s' is not supported yet", req.MessageType)
+	if len([]rune(req.Content)) > maxContentLength {
+		return fmt.Errorf("content exceeds maximum length of %d characters", maxContentLength)
 	}
 
 	return nil
@@ -0,0 +1,18 @@
+//go:generate mockgen -destination=mock_contract_test.go -package=${GOPACKAGE} -source=contract.go
+package presence
+
+import (
+	"context"
+	"time"
+
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+type DBRepo interface {
+	GetUsersOnlineWithin(ctx context.Context, window time.Duration) ([]string, error)
+	UpdateUsersLastOnline(ctx context.Context, userIDs []string) error
+}
+
+type PresenceClient interface {
+	PresenceStatsBatch(ctx context.Context, channels []string) ([]*model.PresenceStats, error)
+}
@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package presence is a generated GoMock package.
+package presence
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/s21platform/chat-service/internal/model"
+)
+
+// MockDBRepo is a mock of DBRepo interface.
+type MockDBRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDBRepoMockRecorder
+}
+
+// MockDBRepoMockRecorder is the mock recorder for MockDBRepo.
+type MockDBRepoMockRecorder struct {
+	mock *MockDBRepo
+}
+
+// NewMockDBRepo creates a new mock instance.
+func NewMockDBRepo(ctrl *gomock.Controller) *MockDBRepo {
+	mock := &MockDBRepo{ctrl: ctrl}
+	mock.recorder = &MockDBRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDBRepo) EXPECT() *MockDBRepoMockRecorder {
+	return m.recorder
+}
+
+// GetUsersOnlineWithin mocks base method.
+func (m *MockDBRepo) GetUsersOnlineWithin(ctx context.Context, window time.Duration) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersOnlineWithin", ctx, window)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersOnlineWithin indicates an expected call of GetUsersOnlineWithin.
+func (mr *MockDBRepoMockRecorder) GetUsersOnlineWithin(ctx, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersOnlineWithin", reflect.TypeOf((*MockDBRepo)(nil).GetUsersOnlineWithin), ctx, window)
+}
+
+// UpdateUsersLastOnline mocks base method.
+func (m *MockDBRepo) UpdateUsersLastOnline(ctx context.Context, userIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUsersLastOnline", ctx, userIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUsersLastOnline indicates an expected call of UpdateUsersLastOnline.
+func (mr *MockDBRepoMockRecorder) UpdateUsersLastOnline(ctx, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUsersLastOnline", reflect.TypeOf((*MockDBRepo)(nil).UpdateUsersLastOnline), ctx, userIDs)
+}
+
+// MockPresenceClient is a mock of PresenceClient interface.
+type MockPresenceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresenceClientMockRecorder
+}
+
+// MockPresenceClientMockRecorder is the mock recorder for MockPresenceClient.
+type MockPresenceClientMockRecorder struct {
+	mock *MockPresenceClient
+}
+
+// NewMockPresenceClient creates a new mock instance.
+func NewMockPresenceClient(ctrl *gomock.Controller) *MockPresenceClient {
+	mock := &MockPresenceClient{ctrl: ctrl}
+	mock.recorder = &MockPresenceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPresenceClient) EXPECT() *MockPresenceClientMockRecorder {
+	return m.recorder
+}
+
+// PresenceStatsBatch mocks base method.
+func (m *MockPresenceClient) PresenceStatsBatch(ctx context.Context, channels []string) ([]*model.PresenceStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresenceStatsBatch", ctx, channels)
+	ret0, _ := ret[0].([]*model.PresenceStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresenceStatsBatch indicates an expected call of PresenceStatsBatch.
+func (mr *MockPresenceClientMockRecorder) PresenceStatsBatch(ctx, channels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresenceStatsBatch", reflect.TypeOf((*MockPresenceClient)(nil).PresenceStatsBatch), ctx, channels)
+}
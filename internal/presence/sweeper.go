@@ -0,0 +1,100 @@
+package presence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	logger_lib "github.com/s21platform/logger-lib"
+
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+// sweepBatchSize — сколько персональных каналов проверяется одним запросом к Centrifugo
+const sweepBatchSize = 100
+
+// Sweeper продлевает last_online пользователям, которые всё ещё подключены. Centrifugo не сообщает
+// об отключении, а connect и refresh proxy обновляют last_online раз в proxyTTL; со sweeper'ом
+// last_online отключившегося пользователя отстаёт от реального не больше чем на interval
+type Sweeper struct {
+	repository DBRepo
+	presence   PresenceClient
+	interval   time.Duration
+	window     time.Duration
+}
+
+func New(repo DBRepo, presence PresenceClient, cfg *config.Config) *Sweeper {
+	return &Sweeper{
+		repository: repo,
+		presence:   presence,
+		interval:   cfg.Centrifuge.PresenceSweepInterval,
+		// подключённый пользователь обновлял last_online не раньше чем proxyTTL назад
+		window: cfg.Centrifuge.ProxyTTL + cfg.Centrifuge.PresenceSweepInterval,
+	}
+}
+
+func (s *Sweeper) Run(ctx context.Context) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("Run")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.Sweep(ctx); err != nil {
+			logger.Error(fmt.Sprintf("failed to sweep presence: %v", err))
+		}
+	}
+}
+
+// Sweep проверяет presence недавно активных пользователей и продлевает last_online тем, кто подключён
+func (s *Sweeper) Sweep(ctx context.Context) error {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("Sweep")
+
+	userIDs, err := s.repository.GetUsersOnlineWithin(ctx, s.window)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(userIDs); start += sweepBatchSize {
+		batch := userIDs[start:min(start+sweepBatchSize, len(userIDs))]
+
+		channels := make([]string, len(batch))
+		for i, userID := range batch {
+			channels[i] = model.PersonalChannel(userID)
+		}
+
+		stats, err := s.presence.PresenceStatsBatch(ctx, channels)
+		if err != nil {
+			if stats == nil {
+				return fmt.Errorf("failed to get presence: %v", err)
+			}
+			logger.Warn(fmt.Sprintf("failed to get presence of some users: %v", err))
+		}
+
+		online := make([]string, 0, len(batch))
+		for i, userID := range batch {
+			if stats[i] != nil && stats[i].NumClients > 0 {
+				online = append(online, userID)
+			}
+		}
+
+		if len(online) == 0 {
+			continue
+		}
+
+		if err := s.repository.UpdateUsersLastOnline(ctx, online); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package presence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	logger_lib "github.com/s21platform/logger-lib"
+
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+func TestSweeper_Sweep(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Centrifuge: config.Centrifuge{
+			ProxyTTL:              10 * time.Minute,
+			PresenceSweepInterval: time.Minute,
+		},
+	}
+
+	t.Run("extends_connected_users", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockPresence := NewMockPresenceClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		ctx := context.WithValue(context.Background(), config.KeyLogger, mockLogger)
+
+		online := uuid.New().String()
+		offline := uuid.New().String()
+		unknown := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("Sweep")
+		mockLogger.EXPECT().Warn(gomock.Any())
+		mockRepo.EXPECT().GetUsersOnlineWithin(gomock.Any(), 11*time.Minute).Return([]string{online, offline, unknown}, nil)
+		mockPresence.EXPECT().PresenceStatsBatch(gomock.Any(), []string{
+			model.PersonalChannel(online),
+			model.PersonalChannel(offline),
+			model.PersonalChannel(unknown),
+		}).Return([]*model.PresenceStats{{NumClients: 1, NumUsers: 1}, {}, nil}, errors.New("not available"))
+		mockRepo.EXPECT().UpdateUsersLastOnline(gomock.Any(), []string{online}).Return(nil)
+
+		require.NoError(t, New(mockRepo, mockPresence, cfg).Sweep(ctx))
+	})
+
+	t.Run("splits_into_batches", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockPresence := NewMockPresenceClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		ctx := context.WithValue(context.Background(), config.KeyLogger, mockLogger)
+
+		userIDs := make([]string, sweepBatchSize+1)
+		for i := range userIDs {
+			userIDs[i] = uuid.New().String()
+		}
+
+		mockLogger.EXPECT().AddFuncName("Sweep")
+		mockRepo.EXPECT().GetUsersOnlineWithin(gomock.Any(), gomock.Any()).Return(userIDs, nil)
+		mockPresence.EXPECT().PresenceStatsBatch(gomock.Any(), gomock.Len(sweepBatchSize)).
+			Return(make([]*model.PresenceStats, sweepBatchSize), nil)
+		mockPresence.EXPECT().PresenceStatsBatch(gomock.Any(), []string{model.PersonalChannel(userIDs[sweepBatchSize])}).
+			Return([]*model.PresenceStats{{NumClients: 2, NumUsers: 1}}, nil)
+		mockRepo.EXPECT().UpdateUsersLastOnline(gomock.Any(), []string{userIDs[sweepBatchSize]}).Return(nil)
+
+		require.NoError(t, New(mockRepo, mockPresence, cfg).Sweep(ctx))
+	})
+
+	t.Run("presence_unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockPresence := NewMockPresenceClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		ctx := context.WithValue(context.Background(), config.KeyLogger, mockLogger)
+
+		userID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("Sweep")
+		mockRepo.EXPECT().GetUsersOnlineWithin(gomock.Any(), gomock.Any()).Return([]string{userID}, nil)
+		mockPresence.EXPECT().PresenceStatsBatch(gomock.Any(), gomock.Any()).Return(nil, errors.New("unexpected status code: 502"))
+
+		require.Error(t, New(mockRepo, mockPresence, cfg).Sweep(ctx))
+	})
+}
@@ -2,8 +2,13 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
@@ -35,25 +40,21 @@ func (r *Repository) Close() {
 	_ = r.connection.Close()
 }
 
-func (r *Repository) GetStreamRecentMessages(ctx context.Context, streamID string, offset string, limit int32) (*model.MessageList, error) {
-	queryBuilder := sq.Select(
-		"id",
-		"stream_id",
-		"sender_id",
-		"type",
-		"content",
-		"root_id",
-		"parent_id",
-		"sent_at",
-		"updated_at",
-	).
-		From("messages").
-		Where(sq.Eq{"stream_id": streamID}).
-		Where(sq.Eq{"deleted_at": nil}).
-		OrderBy("sent_at DESC")
+func (r *Repository) GetStreamRecentMessages(ctx context.Context, streamID, userID string, cursor *model.MessageCursor, direction string, limit int32) (*model.MessageList, error) {
+	queryBuilder := messagesSelect(userID).
+		Where(sq.Eq{"stream_id": streamID})
 
-	if offset != "" {
-		queryBuilder = queryBuilder.Where(sq.LtOrEq{"sent_at": offset})
+	// after отдаёт сообщения от старых к новым, before — от новых к старым
+	if direction == model.AfterCursorDirection {
+		queryBuilder = queryBuilder.OrderBy("sent_at ASC", "id ASC")
+		if cursor != nil {
+			queryBuilder = queryBuilder.Where("(sent_at, id) > (?, ?)", cursor.SentAt, cursor.ID)
+		}
+	} else {
+		queryBuilder = queryBuilder.OrderBy("sent_at DESC", "id DESC")
+		if cursor != nil {
+			queryBuilder = queryBuilder.Where("(sent_at, id) < (?, ?)", cursor.SentAt, cursor.ID)
+		}
 	}
 
 	if limit > 0 {
@@ -76,6 +77,58 @@ func (r *Repository) GetStreamRecentMessages(ctx context.Context, streamID strin
 	return &messages, nil
 }
 
+func (r *Repository) GetStreamMessage(ctx context.Context, streamID, userID, messageID string) (*model.Message, error) {
+	query, args, err := messagesSelect(userID).
+		Where(sq.Eq{"stream_id": streamID}).
+		Where(sq.Eq{"id": messageID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var message model.Message
+	err = r.Chk(ctx).GetContext(ctx, &message, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get stream message: %v", err)
+	}
+
+	return &message, nil
+}
+
+func (r *Repository) GetThreadMessages(ctx context.Context, streamID, rootID, userID string, cursor *model.MessageCursor, limit int32) (*model.MessageList, error) {
+	queryBuilder := messagesSelect(userID).
+		Where(sq.Eq{"stream_id": streamID}).
+		Where(sq.Eq{"root_id": rootID}).
+		OrderBy("sent_at DESC", "id DESC")
+
+	if cursor != nil {
+		queryBuilder = queryBuilder.Where("(sent_at, id) < (?, ?)", cursor.SentAt, cursor.ID)
+	}
+
+	if limit > 0 {
+		queryBuilder = queryBuilder.Limit(uint64(limit))
+	} else {
+		queryBuilder = queryBuilder.Limit(50) // дефолтный лимит
+	}
+
+	query, args, err := queryBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var messages model.MessageList
+	err = r.Chk(ctx).SelectContext(ctx, &messages, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread messages: %v", err)
+	}
+
+	return &messages, nil
+}
+
 func (r *Repository) UpdateUserNickname(ctx context.Context, userUUID, newNickname string) error {
 	query, args, err := sq.Update("chats_user").
 		Set("username", newNickname).
@@ -132,6 +185,119 @@ func (r *Repository) CreateStream(ctx context.Context, streamType, metadata, cre
 	return streamID, nil
 }
 
+// GetOrCreatePrivateStream создаёт личный стрим пары или возвращает существующий; created — стрим создан этим вызовом
+func (r *Repository) GetOrCreatePrivateStream(ctx context.Context, metadata, createdBy, pair string) (string, bool, error) {
+	query, args, err := sq.Insert("streams").
+		Columns("type", "metadata", "created_by", "private_pair").
+		Values(model.PrivateStreamType, metadata, createdBy, pair).
+		Suffix("ON CONFLICT (private_pair) WHERE type = 'private' DO UPDATE SET private_pair = EXCLUDED.private_pair RETURNING id, (xmax = 0) AS created").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var result struct {
+		ID      string `db:"id"`
+		Created bool   `db:"created"`
+	}
+	err = r.Chk(ctx).GetContext(ctx, &result, query, args...)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get or create private stream: %v", err)
+	}
+
+	return result.ID, result.Created, nil
+}
+
+func (r *Repository) GetPrivateStreamByPair(ctx context.Context, pair string) (string, error) {
+	query, args, err := sq.Select("id").
+		From("streams").
+		Where(sq.Eq{
+			"type":         model.PrivateStreamType,
+			"private_pair": pair,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var streamID string
+	err = r.Chk(ctx).GetContext(ctx, &streamID, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get private stream: %v", err)
+	}
+
+	return streamID, nil
+}
+
+// UpdateStreamMetadata дописывает patch в metadata стрима и возвращает итоговые метаданные
+func (r *Repository) UpdateStreamMetadata(ctx context.Context, streamID, patch string) (*model.StreamMetadata, error) {
+	query, args, err := sq.Update("streams").
+		Set("metadata", sq.Expr("COALESCE(metadata, '{}'::jsonb) || ?::jsonb", patch)).
+		Where(sq.Eq{"id": streamID}).
+		Suffix("RETURNING metadata").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var metadata model.StreamMetadata
+	err = r.Chk(ctx).GetContext(ctx, &metadata, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update stream metadata: %v", err)
+	}
+
+	return &metadata, nil
+}
+
+func (r *Repository) GetOrCreateCommentStream(ctx context.Context, entityType, entityID string) (string, error) {
+	query, args, err := sq.Insert("streams").
+		Columns("type", "metadata", "entity_type", "entity_id").
+		Values(model.CommentStreamType, "{}", entityType, entityID).
+		Suffix("ON CONFLICT (entity_type, entity_id) WHERE type = 'comment' DO UPDATE SET entity_id = EXCLUDED.entity_id RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var streamID string
+	err = r.Chk(ctx).GetContext(ctx, &streamID, query, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get or create comment stream: %v", err)
+	}
+
+	return streamID, nil
+}
+
+func (r *Repository) CountStreamMessages(ctx context.Context, streamID string) (int64, error) {
+	query, args, err := sq.Select("COUNT(*)").
+		From("messages").
+		Where(sq.Eq{"stream_id": streamID}).
+		Where(sq.Eq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var count int64
+	err = r.Chk(ctx).GetContext(ctx, &count, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count stream messages: %v", err)
+	}
+
+	return count, nil
+}
+
 func (r *Repository) AddNewUser(ctx context.Context, userInfo *model.StreamMemberParams) error {
 	query, args, err := sq.Insert("users").
 		Columns("id", "nickname", "avatar_url").
@@ -148,78 +314,106 @@ func (r *Repository) AddNewUser(ctx context.Context, userInfo *model.StreamMembe
 	return err
 }
 
-func (r *Repository) AddStreamMembers(ctx context.Context, streamID string, members []model.StreamMember) error {
-	if len(members) == 0 {
-		return nil
+func (r *Repository) UpdateUserLastOnline(ctx context.Context, userID string) error {
+	query, args, err := sq.Update("users").
+		Set("last_online", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
 	}
 
-	query := sq.Insert("stream_members").
-		Columns("stream_id", "user_id", "metadata").
-		PlaceholderFormat(sq.Dollar)
-
-	for _, member := range members {
-		query = query.Values(streamID, member.UserID, member.Metadata)
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update user last online: %v", err)
 	}
 
-	sql, args, err := query.ToSql()
+	return nil
+}
+
+// UpdateUsersLastOnline продлевает last_online пользователям, которых presence показывает подключёнными
+func (r *Repository) UpdateUsersLastOnline(ctx context.Context, userIDs []string) error {
+	query, args, err := sq.Update("users").
+		Set("last_online", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": userIDs}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build sql query: %v", err)
 	}
 
-	_, err = r.Chk(ctx).ExecContext(ctx, sql, args...)
-	return err
-}
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update users last online: %v", err)
+	}
 
-func (r *Repository) SaveMessage(ctx context.Context, message *model.Message) error {
-	query := sq.Insert("messages").
-		Columns("id", "stream_id", "sender_id", "type", "content", "root_id", "parent_id").
-		Values(message.ID, message.StreamID, message.SenderID, message.Type, message.Content, message.RootID, message.ParentID).
-		PlaceholderFormat(sq.Dollar)
+	return nil
+}
 
-	sql, args, err := query.ToSql()
+// GetUsersOnlineWithin возвращает пользователей, которые были онлайн за последние window;
+// окно считается по часам базы, как и сам last_online
+func (r *Repository) GetUsersOnlineWithin(ctx context.Context, window time.Duration) ([]string, error) {
+	query, args, err := sq.Select("id").
+		From("users").
+		Where(sq.Expr("last_online >= CURRENT_TIMESTAMP - make_interval(secs => ?)", window.Seconds())).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
 	if err != nil {
-		return fmt.Errorf("failed to build sql query: %v", err)
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
 	}
 
-	_, err = r.Chk(ctx).ExecContext(ctx, sql, args...)
+	var userIDs []string
+	err = r.Chk(ctx).SelectContext(ctx, &userIDs, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to save message: %v", err)
+		return nil, fmt.Errorf("failed to get users online within %s: %v", window, err)
 	}
 
-	return nil
+	return userIDs, nil
 }
 
-func (r *Repository) IsStreamMember(ctx context.Context, streamID, userID string) (bool, error) {
+func (r *Repository) GetUserSnapshot(ctx context.Context, userID string) (*model.UserSnapshot, error) {
 	query, args, err := sq.
-		Select("COUNT(*) > 0").
-		From("stream_members").
-		Where(sq.And{
-			sq.Eq{"stream_id": streamID},
-			sq.Eq{"user_id": userID},
-		}).
+		Select("id", "nickname", "avatar_url").
+		From("users").
+		Where(sq.Eq{"id": userID}).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
-		return false, fmt.Errorf("failed to build sql query: %v", err)
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
 	}
 
-	var isMember bool
-	err = r.Chk(ctx).GetContext(ctx, &isMember, query, args...)
+	var snapshot model.UserSnapshot
+	err = r.Chk(ctx).GetContext(ctx, &snapshot, query, args...)
 	if err != nil {
-		return false, fmt.Errorf("failed to check stream membership: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user snapshot: %v", err)
 	}
 
-	return isMember, nil
+	return &snapshot, nil
 }
 
-func (r *Repository) AddUserSubscriptions(ctx context.Context, subscriptions []model.UserSubscription) error {
-	query := sq.Insert("user_subscriptions").
-		Columns("user_id", "channel").
-		Suffix("ON CONFLICT (user_id, channel) DO NOTHING").
+func (r *Repository) AddStreamMembers(ctx context.Context, streamID string, members []model.StreamMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	// вышедший ранее участник возвращается в ту же строку: unique_stream_user не даёт вставить новую
+	query := sq.Insert("stream_members").
+		Columns("stream_id", "user_id", "metadata", "role").
+		Suffix(`ON CONFLICT (stream_id, user_id) DO UPDATE
+			SET left_at = NULL, joined_at = CURRENT_TIMESTAMP, metadata = EXCLUDED.metadata, role = EXCLUDED.role
+			WHERE stream_members.left_at IS NOT NULL`).
 		PlaceholderFormat(sq.Dollar)
 
-	for _, sub := range subscriptions {
-		query = query.Values(sub.UserID, sub.Channel)
+	for _, member := range members {
+		role := member.Role
+		if role == "" {
+			role = model.MemberRole
+		}
+		query = query.Values(streamID, member.UserID, member.Metadata, role)
 	}
 
 	sql, args, err := query.ToSql()
@@ -228,80 +422,957 @@ func (r *Repository) AddUserSubscriptions(ctx context.Context, subscriptions []m
 	}
 
 	_, err = r.Chk(ctx).ExecContext(ctx, sql, args...)
-
 	return err
 }
 
-func (r *Repository) GetPrivateStreams(ctx context.Context, requesterID string) (*model.PrivateStreamPreviewList, error) {
-	query := sq.Select(
-		"s.id as stream_id",
-		"u_companion.nickname as stream_name",
-		"u_companion.avatar_url",
-		"("+func() string {
-			sql, _, _ := sq.Select("content").
-				From("messages m2").
-				Where("m2.stream_id = s.id").
-				Where(sq.Eq{"m2.deleted_at": nil}).
-				OrderBy("m2.sent_at DESC").
-				Limit(1).ToSql()
-			return sql
-		}()+") as last_message_content",
-		"("+func() string {
-			sql, _, _ := sq.Select("sent_at").
-				From("messages m2").
-				Where("m2.stream_id = s.id").
-				Where(sq.Eq{"m2.deleted_at": nil}).
-				OrderBy("m2.sent_at DESC").
-				Limit(1).ToSql()
-			return sql
-		}()+") as last_message_timestamp",
-	).
-		From("streams s").
-		Join("stream_members sm1 ON s.id = sm1.stream_id").
-		Join("stream_members sm2 ON s.id = sm2.stream_id").
-		Join("users u_companion ON sm2.user_id = u_companion.id").
-		Where(sq.And{
-			sq.Eq{"sm1.user_id": requesterID},
-			sq.NotEq{"sm2.user_id": requesterID},
-			sq.Eq{"sm1.left_at": nil},
-			sq.Eq{"sm2.left_at": nil},
+func (r *Repository) UpdateMemberRole(ctx context.Context, streamID, userID, role string) error {
+	query, args, err := sq.Update("stream_members").
+		Set("role", role).
+		Where(sq.Eq{
+			"stream_id": streamID,
+			"user_id":   userID,
+			"left_at":   nil,
 		}).
-		OrderBy("s.created_at DESC").
-		PlaceholderFormat(sq.Dollar)
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
 
-	sql, args, err := query.ToSql()
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update member role: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateNotifyLevel меняет уровень уведомлений участника; nil — пользователь не состоит в стриме
+func (r *Repository) UpdateNotifyLevel(ctx context.Context, streamID, userID, level string) (*model.NotificationSettings, error) {
+	return r.updateNotificationSettings(ctx, streamID, userID, map[string]interface{}{
+		"notify_level": level,
+	})
+}
+
+// UpdateStreamMute глушит стрим навсегда (until = nil) или до until либо снимает заглушку; nil — пользователь не состоит в стриме
+func (r *Repository) UpdateStreamMute(ctx context.Context, streamID, userID string, muted bool, until *time.Time) (*model.NotificationSettings, error) {
+	return r.updateNotificationSettings(ctx, streamID, userID, map[string]interface{}{
+		"notify":      !muted,
+		"muted_until": until,
+	})
+}
+
+func (r *Repository) updateNotificationSettings(ctx context.Context, streamID, userID string, changes map[string]interface{}) (*model.NotificationSettings, error) {
+	query, args, err := sq.Update("stream_members").
+		SetMap(changes).
+		Where(sq.Eq{
+			"stream_id": streamID,
+			"user_id":   userID,
+			"left_at":   nil,
+		}).
+		Suffix("RETURNING notify, notify_level, muted_until").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sql query: %v", err)
 	}
 
-	var streams model.PrivateStreamPreviewList
-	err = r.Chk(ctx).SelectContext(ctx, &streams, sql, args...)
+	var settings model.NotificationSettings
+	err = r.Chk(ctx).GetContext(ctx, &settings, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get streams: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update notification settings: %v", err)
 	}
 
-	return &streams, nil
+	return &settings, nil
 }
 
-func (r *Repository) GetUserActiveStreams(ctx context.Context, userID string) ([]string, error) {
-	queryBuilder := sq.Select("stream_id").
-		From("stream_members").
+// LeaveStream помечает участие завершённым; false — пользователь и так не состоял в стриме
+func (r *Repository) LeaveStream(ctx context.Context, streamID, userID string) (bool, error) {
+	query, args, err := sq.Update("stream_members").
+		Set("left_at", sq.Expr("CURRENT_TIMESTAMP")).
 		Where(sq.Eq{
-			"user_id": userID,
-			"left_at": nil,
+			"stream_id": streamID,
+			"user_id":   userID,
+			"left_at":   nil,
 		}).
+		Suffix("RETURNING left_at").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var leftAt time.Time
+	err = r.Chk(ctx).GetContext(ctx, &leftAt, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to leave stream: %v", err)
+	}
+
+	return true, nil
+}
+
+func (r *Repository) SaveMessage(ctx context.Context, message *model.Message) error {
+	query := sq.Insert("messages").
+		Columns("id", "stream_id", "sender_id", "type", "content", "media", "root_id", "parent_id").
+		Values(message.ID, message.StreamID, message.SenderID, message.Type, message.Content, message.Media, message.RootID, message.ParentID).
+		Suffix("RETURNING sent_at").
 		PlaceholderFormat(sq.Dollar)
 
-	sql, args, err := queryBuilder.ToSql()
+	sql, args, err := query.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build sql query: %v", err)
+		return fmt.Errorf("failed to build sql query: %v", err)
 	}
 
-	var streamIDs []string
-	err = r.Chk(ctx).SelectContext(ctx, &streamIDs, sql, args...)
+	// sent_at входит в курсор пагинации, поэтому в модель возвращается значение из БД
+	err = r.Chk(ctx).GetContext(ctx, &message.SentAt, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user active streams: %v", err)
+		return fmt.Errorf("failed to save message: %v", err)
 	}
 
-	return streamIDs, nil
+	// последнее сообщение хранится в стриме, чтобы список стримов не искал его по messages;
+	// условие на (sent_at, id) не даёт более раннему сообщению перетереть более позднее
+	sql, args, err = sq.Update("streams s").
+		Set("last_message_id", sq.Expr("m.id")).
+		Set("last_message_content", sq.Expr("m.content")).
+		Set("last_message_sender_id", sq.Expr("m.sender_id")).
+		Set("last_message_at", sq.Expr("m.sent_at")).
+		From("messages m").
+		Where(sq.Eq{"m.id": message.ID}).
+		Where("s.id = m.stream_id").
+		Where("(s.last_message_at IS NULL OR (s.last_message_at, s.last_message_id) < (m.sent_at, m.id))").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update stream last message: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetMessage(ctx context.Context, messageID string) (*model.Message, error) {
+	query, args, err := sq.Select(
+		"id",
+		"stream_id",
+		"sender_id",
+		"type",
+		"content",
+		"media",
+		"root_id",
+		"parent_id",
+		"sent_at",
+		"updated_at",
+		"deleted_at",
+	).
+		From("messages").
+		Where(sq.Eq{"id": messageID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var message model.Message
+	err = r.Chk(ctx).GetContext(ctx, &message, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get message: %v", err)
+	}
+
+	return &message, nil
+}
+
+func (r *Repository) UpdateMessageContent(ctx context.Context, messageID, content string) (time.Time, error) {
+	query, args, err := sq.Update("messages").
+		Set("content", content).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": messageID}).
+		Suffix("RETURNING updated_at").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var updatedAt time.Time
+	err = r.Chk(ctx).GetContext(ctx, &updatedAt, query, args...)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to update message content: %v", err)
+	}
+
+	query, args, err = sq.Update("streams").
+		Set("last_message_content", content).
+		Where(sq.Eq{"last_message_id": messageID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to update stream last message: %v", err)
+	}
+
+	return updatedAt, nil
+}
+
+func (r *Repository) HideMessage(ctx context.Context, messageID, userID string) error {
+	query, args, err := sq.Insert("message_hides").
+		Columns("message_id", "user_id").
+		Values(messageID, userID).
+		Suffix("ON CONFLICT (message_id, user_id) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to hide message: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteMessage(ctx context.Context, messageID, deletedBy string) (time.Time, error) {
+	query, args, err := sq.Update("messages").
+		Set("deleted_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Set("deleted_by", deletedBy).
+		Set("delete_format", model.AllDeleteFormat).
+		Where(sq.Eq{"id": messageID}).
+		Suffix("RETURNING deleted_at").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var deletedAt time.Time
+	err = r.Chk(ctx).GetContext(ctx, &deletedAt, query, args...)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to delete message: %v", err)
+	}
+
+	// удалили последнее сообщение стрима — последним становится предыдущее неудалённое
+	query, args, err = sq.Update("streams s").
+		Set("(last_message_id, last_message_content, last_message_sender_id, last_message_at)", sq.Expr("("+lastMessageSubquery("m2.id, m2.content, m2.sender_id, m2.sent_at")+")")).
+		Where(sq.Eq{"s.last_message_id": messageID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to update stream last message: %v", err)
+	}
+
+	return deletedAt, nil
+}
+
+func (r *Repository) MarkStreamRead(ctx context.Context, streamID, userID, messageID string) (*time.Time, error) {
+	query, args, err := sq.Insert("message_reads").
+		Columns("stream_id", "user_id", "message_id").
+		Values(streamID, userID, messageID).
+		Suffix(`ON CONFLICT (stream_id, user_id) DO UPDATE
+			SET message_id = EXCLUDED.message_id, read_at = CURRENT_TIMESTAMP
+			WHERE EXISTS (
+				SELECT 1 FROM messages n, messages o
+				WHERE n.id = EXCLUDED.message_id AND o.id = message_reads.message_id
+				  AND (n.sent_at, n.id) > (o.sent_at, o.id)
+			)
+			RETURNING read_at`).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var readAt time.Time
+	err = r.Chk(ctx).GetContext(ctx, &readAt, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to mark stream read: %v", err)
+	}
+
+	return &readAt, nil
+}
+
+func (r *Repository) GetStreamReadMarks(ctx context.Context, streamID string, since model.MessageCursor) (model.ReadMarkList, error) {
+	query, args, err := sq.Select(
+		"mr.stream_id",
+		"mr.user_id",
+		"mr.message_id",
+		"m.sent_at",
+		"mr.read_at",
+	).
+		From("message_reads mr").
+		Join("messages m ON m.id = mr.message_id").
+		Where(sq.Eq{"mr.stream_id": streamID}).
+		Where("(m.sent_at, m.id) >= (?, ?)", since.SentAt, since.ID).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var marks model.ReadMarkList
+	err = r.Chk(ctx).SelectContext(ctx, &marks, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream read marks: %v", err)
+	}
+
+	return marks, nil
+}
+
+func (r *Repository) IsStreamMember(ctx context.Context, streamID, userID string) (bool, error) {
+	query, args, err := sq.
+		Select("COUNT(*) > 0").
+		From("stream_members").
+		Where(sq.And{
+			sq.Eq{"stream_id": streamID},
+			sq.Eq{"user_id": userID},
+			sq.Eq{"left_at": nil},
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var isMember bool
+	err = r.Chk(ctx).GetContext(ctx, &isMember, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to check stream membership: %v", err)
+	}
+
+	return isMember, nil
+}
+
+func (r *Repository) GetStreamMembership(ctx context.Context, streamID, userID string) (*model.StreamMembership, error) {
+	query, args, err := sq.
+		Select("sm.stream_id", "s.type as stream_type", "sm.role").
+		From("stream_members sm").
+		Join("streams s ON s.id = sm.stream_id").
+		Where(sq.And{
+			sq.Eq{"sm.stream_id": streamID},
+			sq.Eq{"sm.user_id": userID},
+			sq.Eq{"sm.left_at": nil},
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var membership model.StreamMembership
+	err = r.Chk(ctx).GetContext(ctx, &membership, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get stream membership: %v", err)
+	}
+
+	return &membership, nil
+}
+
+func (r *Repository) GetStreamType(ctx context.Context, streamID string) (string, error) {
+	query, args, err := sq.
+		Select("type").
+		From("streams").
+		Where(sq.Eq{"id": streamID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var streamType string
+	err = r.Chk(ctx).GetContext(ctx, &streamType, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get stream type: %v", err)
+	}
+
+	return streamType, nil
+}
+
+func (r *Repository) AddUserSubscriptions(ctx context.Context, subscriptions []model.UserSubscription) error {
+	query := sq.Insert("user_subscriptions").
+		Columns("user_id", "channel").
+		Suffix("ON CONFLICT (user_id, channel) DO NOTHING").
+		PlaceholderFormat(sq.Dollar)
+
+	for _, sub := range subscriptions {
+		query = query.Values(sub.UserID, sub.Channel)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+func (r *Repository) RemoveUserSubscription(ctx context.Context, userID, channel string) error {
+	query, args, err := sq.Delete("user_subscriptions").
+		Where(sq.Eq{
+			"user_id": userID,
+			"channel": channel,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to remove user subscription: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetStreamPersonalChannels(ctx context.Context, streamID string, userIDs []string) ([]model.PersonalChannelMember, error) {
+	queryBuilder := sq.Select(
+		"sm.user_id",
+		"us.channel",
+		"("+unreadCountSubquery("sm")+") as unread_count",
+	).
+		From("stream_members sm").
+		Join("streams s ON s.id = sm.stream_id").
+		Join("user_subscriptions us ON us.user_id = sm.user_id AND us.channel = '" + model.PersonalChannelPrefix + "' || sm.user_id").
+		Where(sq.Eq{
+			"sm.stream_id": streamID,
+			"sm.left_at":   nil,
+		})
+
+	if len(userIDs) > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"sm.user_id": userIDs})
+	}
+
+	query, args, err := queryBuilder.
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var members []model.PersonalChannelMember
+	err = r.Chk(ctx).SelectContext(ctx, &members, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream personal channels: %v", err)
+	}
+
+	return members, nil
+}
+
+func (r *Repository) GetPrivateStreams(ctx context.Context, requesterID string) (*model.PrivateStreamPreviewList, error) {
+	query := sq.Select(
+		"s.id as stream_id",
+		"u_companion.nickname as stream_name",
+		"u_companion.avatar_url",
+		"u_companion.id as companion_id",
+		"u_companion.last_online as companion_last_online",
+		"s.last_message_content",
+		"s.last_message_at as last_message_timestamp",
+		"("+unreadCountSubquery("sm1")+") as unread_count",
+		mutedColumn("sm1")+" as muted",
+		"sm1.notify_level",
+	).
+		From("streams s").
+		Join("stream_members sm1 ON s.id = sm1.stream_id").
+		Join("stream_members sm2 ON s.id = sm2.stream_id").
+		Join("users u_companion ON sm2.user_id = u_companion.id").
+		Where(sq.And{
+			sq.Eq{"s.type": model.PrivateStreamType},
+			sq.Eq{"sm1.user_id": requesterID},
+			sq.NotEq{"sm2.user_id": requesterID},
+			sq.Eq{"sm1.left_at": nil},
+			sq.Eq{"sm2.left_at": nil},
+		}).
+		OrderBy(streamActivityColumn+" DESC", "s.id DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var streams model.PrivateStreamPreviewList
+	err = r.Chk(ctx).SelectContext(ctx, &streams, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get streams: %v", err)
+	}
+
+	return &streams, nil
+}
+
+func (r *Repository) GetGroupStreams(ctx context.Context, requesterID string) (*model.GroupStreamPreviewList, error) {
+	query := sq.Select(
+		"s.id as stream_id",
+		"s.metadata->>'title' as stream_name",
+		"COALESCE(s.metadata->>'avatar_url', '') as avatar_url",
+		"s.last_message_content",
+		"s.last_message_at as last_message_timestamp",
+		"("+unreadCountSubquery("sm")+") as unread_count",
+		mutedColumn("sm")+" as muted",
+		"sm.notify_level",
+	).
+		From("streams s").
+		Join("stream_members sm ON s.id = sm.stream_id").
+		Where(sq.And{
+			sq.Eq{"s.type": model.GroupStreamType},
+			sq.Eq{"sm.user_id": requesterID},
+			sq.Eq{"sm.left_at": nil},
+		}).
+		OrderBy(streamActivityColumn+" DESC", "s.id DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var streams model.GroupStreamPreviewList
+	err = r.Chk(ctx).SelectContext(ctx, &streams, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get streams: %v", err)
+	}
+
+	return &streams, nil
+}
+
+func (r *Repository) GetChannelStreams(ctx context.Context, requesterID string) (*model.ChannelStreamPreviewList, error) {
+	query := sq.Select(
+		"s.id as stream_id",
+		"s.metadata->>'title' as stream_name",
+		"COALESCE(s.metadata->>'avatar_url', '') as avatar_url",
+		"s.last_message_content",
+		"s.last_message_at as last_message_timestamp",
+		"("+unreadCountSubquery("sm")+") as unread_count",
+		"sm.role",
+		mutedColumn("sm")+" as muted",
+		"sm.notify_level",
+	).
+		From("streams s").
+		Join("stream_members sm ON s.id = sm.stream_id").
+		Where(sq.And{
+			sq.Eq{"s.type": model.ChannelStreamType},
+			sq.Eq{"sm.user_id": requesterID},
+			sq.Eq{"sm.left_at": nil},
+		}).
+		OrderBy(streamActivityColumn+" DESC", "s.id DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var streams model.ChannelStreamPreviewList
+	err = r.Chk(ctx).SelectContext(ctx, &streams, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get streams: %v", err)
+	}
+
+	return &streams, nil
+}
+
+// GetStreams возвращает стримы всех типов, где пользователь сейчас состоит, от недавно активных к давним
+func (r *Repository) GetStreams(ctx context.Context, requesterID, streamType string, cursor *model.StreamCursor, limit int) (*model.StreamPreviewList, error) {
+	queryBuilder := sq.Select(
+		"s.id as stream_id",
+		"s.type as stream_type",
+		"COALESCE(CASE WHEN s.type = 'private' THEN u_companion.nickname ELSE s.metadata->>'title' END, '') as stream_name",
+		"COALESCE(CASE WHEN s.type = 'private' THEN u_companion.avatar_url ELSE s.metadata->>'avatar_url' END, '') as avatar_url",
+		"sm.role",
+		"u_companion.id as companion_id",
+		"s.last_message_id",
+		"s.last_message_content",
+		"s.last_message_sender_id",
+		"u_sender.nickname as last_message_sender_nickname",
+		"s.last_message_at as last_message_timestamp",
+		streamActivityColumn+" as activity_at",
+		"("+unreadCountSubquery("sm")+") as unread_count",
+		mutedColumn("sm")+" as muted",
+		"sm.notify_level",
+		"sm.muted_until",
+	).
+		From("stream_members sm").
+		Join("streams s ON s.id = sm.stream_id").
+		LeftJoin("stream_members sm_companion ON s.type = 'private' AND sm_companion.stream_id = s.id AND sm_companion.user_id <> sm.user_id").
+		LeftJoin("users u_companion ON u_companion.id = sm_companion.user_id").
+		LeftJoin("users u_sender ON u_sender.id = s.last_message_sender_id").
+		Where(sq.Eq{
+			"sm.user_id": requesterID,
+			"sm.left_at": nil,
+		}).
+		OrderBy(streamActivityColumn+" DESC", "s.id DESC").
+		Limit(uint64(limit))
+
+	if streamType != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"s.type": streamType})
+	}
+
+	if cursor != nil {
+		queryBuilder = queryBuilder.Where("("+streamActivityColumn+", s.id) < (?, ?)", cursor.ActivityAt, cursor.ID)
+	}
+
+	query, args, err := queryBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var streams model.StreamPreviewList
+	err = r.Chk(ctx).SelectContext(ctx, &streams, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get streams: %v", err)
+	}
+
+	return &streams, nil
+}
+
+func (r *Repository) GetUserActiveStreams(ctx context.Context, userID string) ([]string, error) {
+	queryBuilder := sq.Select("stream_id").
+		From("stream_members").
+		Where(sq.Eq{
+			"user_id": userID,
+			"left_at": nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var streamIDs []string
+	err = r.Chk(ctx).SelectContext(ctx, &streamIDs, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user active streams: %v", err)
+	}
+
+	return streamIDs, nil
+}
+
+// стрим без сообщений считается активным с момента создания
+const streamActivityColumn = "COALESCE(s.last_message_at, s.created_at)"
+
+func lastMessageSubquery(columns string) string {
+	sql, _, _ := sq.Select(columns).
+		From("messages m2").
+		Where("m2.stream_id = s.id").
+		Where(sq.Eq{"m2.deleted_at": nil}).
+		OrderBy("m2.sent_at DESC", "m2.id DESC").
+		Limit(1).ToSql()
+	return sql
+}
+
+// mutedColumn повторяет NotificationSettings.Muted: истёкший muted_until стрим уже не глушит
+func mutedColumn(memberAlias string) string {
+	return "(NOT " + memberAlias + ".notify AND (" + memberAlias + ".muted_until IS NULL OR " + memberAlias + ".muted_until > CURRENT_TIMESTAMP))"
+}
+
+func unreadCountSubquery(memberAlias string) string {
+	sql, _, _ := sq.Select("COUNT(*)").
+		From("messages mu").
+		LeftJoin("message_reads mr ON mr.stream_id = mu.stream_id AND mr.user_id = " + memberAlias + ".user_id").
+		LeftJoin("messages mlr ON mlr.id = mr.message_id").
+		Where("mu.stream_id = s.id").
+		Where(sq.Eq{"mu.deleted_at": nil}).
+		Where("mu.sender_id <> " + memberAlias + ".user_id").
+		Where("(mlr.id IS NULL OR (mu.sent_at, mu.id) > (mlr.sent_at, mlr.id))").
+		Where("NOT EXISTS (SELECT 1 FROM message_hides mh WHERE mh.message_id = mu.id AND mh.user_id = " + memberAlias + ".user_id)").
+		ToSql()
+	return sql
+}
+
+func messagesSelect(userID string) sq.SelectBuilder {
+	queryBuilder := sq.Select(
+		"id",
+		"stream_id",
+		"sender_id",
+		"type",
+		"content",
+		"media",
+		"root_id",
+		"parent_id",
+		"sent_at",
+		"updated_at",
+		"(SELECT COUNT(*) FROM messages replies WHERE replies.root_id = messages.id AND replies.deleted_at IS NULL) as reply_count",
+	).
+		From("messages").
+		Where(sq.Eq{"deleted_at": nil})
+
+	if userID != "" {
+		queryBuilder = queryBuilder.Where(
+			"NOT EXISTS (SELECT 1 FROM message_hides mh WHERE mh.message_id = messages.id AND mh.user_id = ?)", userID,
+		)
+	}
+
+	return queryBuilder
+}
+
+func (r *Repository) SaveAttachment(ctx context.Context, attachment *model.Attachment) error {
+	query, args, err := sq.Insert("attachments").
+		Columns("storage_key", "uploader_id", "mime_type", "size", "width", "height", "thumbnail_key").
+		Values(attachment.StorageKey, attachment.UploaderID, attachment.MimeType, attachment.Size,
+			attachment.Width, attachment.Height, attachment.ThumbnailKey).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save attachment: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetAttachment(ctx context.Context, storageKey string) (*model.Attachment, error) {
+	query, args, err := sq.
+		Select("storage_key", "uploader_id", "mime_type", "size", "width", "height", "thumbnail_key", "created_at").
+		From("attachments").
+		Where(sq.Eq{"storage_key": storageKey}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var attachment model.Attachment
+	err = r.Chk(ctx).GetContext(ctx, &attachment, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get attachment: %v", err)
+	}
+
+	return &attachment, nil
+}
+
+func (r *Repository) EnqueueOutbox(ctx context.Context, channel string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %v", err)
+	}
+
+	query, args, err := sq.Insert("outbox").
+		Columns("channel", "payload").
+		Values(channel, payload).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue outbox entry: %v", err)
+	}
+
+	query, args, err = sq.Insert("events").
+		Columns("channel", "payload").
+		Values(channel, payload).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save event: %v", err)
+	}
+
+	return nil
+}
+
+// GetEventsAfter возвращает события после курсора из персонального канала пользователя и каналов стримов,
+// где он сейчас состоит; непустой streamIDs оставляет только каналы этих стримов. События транзакций,
+// которые ещё могут закоммититься, не возвращаются, см. model.EventCursor
+func (r *Repository) GetEventsAfter(ctx context.Context, userID string, streamIDs []string, after model.EventCursor, limit int) ([]model.Event, error) {
+	queryBuilder := sq.Select("id", "xid::text AS xid", "channel", "payload->>'type' AS type", "payload").
+		From("events").
+		Where(sq.Expr("(xid, id) > (?::xid8, ?)", strconv.FormatUint(after.Xid, 10), after.ID)).
+		Where("xid < pg_snapshot_xmin(pg_current_snapshot())").
+		Where(sq.Or{
+			sq.Eq{"channel": model.PersonalChannel(userID)},
+			sq.Expr("channel IN (SELECT sm.stream_id::text FROM stream_members sm WHERE sm.user_id = ? AND sm.left_at IS NULL)", userID),
+		}).
+		OrderBy("xid ASC", "id ASC").
+		Limit(uint64(limit))
+
+	if len(streamIDs) > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"channel": streamIDs})
+	}
+
+	query, args, err := queryBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var events []model.Event
+	err = r.Chk(ctx).SelectContext(ctx, &events, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events after cursor: %v", err)
+	}
+
+	return events, nil
+}
+
+// GetLastEventCursor возвращает позицию последнего завершённого события или нулевой курсор, если журнал пуст
+func (r *Repository) GetLastEventCursor(ctx context.Context) (model.EventCursor, error) {
+	query, args, err := sq.Select("id", "xid::text AS xid").
+		From("events").
+		Where("xid < pg_snapshot_xmin(pg_current_snapshot())").
+		OrderBy("xid DESC", "id DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return model.EventCursor{}, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var event model.Event
+	err = r.Chk(ctx).GetContext(ctx, &event, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.EventCursor{}, nil
+		}
+		return model.EventCursor{}, fmt.Errorf("failed to get last event cursor: %v", err)
+	}
+
+	return event.Cursor(), nil
+}
+
+func (r *Repository) DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := sq.Delete("events").
+		Where(sq.Lt{"created_at": before}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	result, err := r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete events: %v", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// TryLockOutbox берёт транзакционную advisory-блокировку, чтобы записи разбирал только один relay
+func (r *Repository) TryLockOutbox(ctx context.Context) (bool, error) {
+	var locked bool
+	err := r.Chk(ctx).GetContext(ctx, &locked, "SELECT pg_try_advisory_xact_lock(hashtext('outbox_relay'))")
+	if err != nil {
+		return false, fmt.Errorf("failed to lock outbox: %v", err)
+	}
+
+	return locked, nil
+}
+
+func (r *Repository) GetPendingOutbox(ctx context.Context, limit int) ([]model.OutboxEntry, error) {
+	// каналы, у которых головная запись ждёт повтора, пропускаются целиком, чтобы не нарушить порядок
+	query, args, err := sq.
+		Select("id", "channel", "payload", "attempts", "created_at").
+		From("outbox").
+		Where("channel NOT IN (SELECT channel FROM outbox WHERE next_attempt_at > LOCALTIMESTAMP)").
+		OrderBy("id").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	var entries []model.OutboxEntry
+	err = r.Chk(ctx).SelectContext(ctx, &entries, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending outbox entries: %v", err)
+	}
+
+	return entries, nil
+}
+
+func (r *Repository) DeleteOutboxEntry(ctx context.Context, id int64) error {
+	query, args, err := sq.Delete("outbox").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete outbox entry: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) MarkOutboxAttemptFailed(ctx context.Context, id int64, backoff time.Duration, lastError string) error {
+	query, args, err := sq.Update("outbox").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("last_error", lastError).
+		Set("next_attempt_at", sq.Expr("LOCALTIMESTAMP + make_interval(secs => ?)", backoff.Seconds())).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build sql query: %v", err)
+	}
+
+	_, err = r.Chk(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox attempt: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetOutboxStats(ctx context.Context) (*model.OutboxStats, error) {
+	query := `SELECT COUNT(*) AS pending,
+		COALESCE(EXTRACT(EPOCH FROM LOCALTIMESTAMP - MIN(created_at)), 0)::float8 AS lag_seconds
+		FROM outbox`
+
+	var stats model.OutboxStats
+	err := r.Chk(ctx).GetContext(ctx, &stats, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox stats: %v", err)
+	}
+
+	return &stats, nil
 }
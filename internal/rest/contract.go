@@ -3,6 +3,8 @@ package rest
 
 import (
 	"context"
+	"io"
+	"time"
 
 	api "github.com/s21platform/chat-service/internal/generated"
 	"github.com/s21platform/chat-service/internal/model"
@@ -10,14 +12,44 @@ import (
 
 type DBRepo interface {
 	CreateStream(ctx context.Context, streamType, metadata, createdBy string) (string, error)
+	GetOrCreatePrivateStream(ctx context.Context, metadata, createdBy, pair string) (string, bool, error)
+	GetPrivateStreamByPair(ctx context.Context, pair string) (string, error)
 	AddStreamMembers(ctx context.Context, streamID string, members []model.StreamMember) error
+	LeaveStream(ctx context.Context, streamID, userID string) (bool, error)
+	UpdateMemberRole(ctx context.Context, streamID, userID, role string) error
+	UpdateNotifyLevel(ctx context.Context, streamID, userID, level string) (*model.NotificationSettings, error)
+	UpdateStreamMute(ctx context.Context, streamID, userID string, muted bool, until *time.Time) (*model.NotificationSettings, error)
+	UpdateStreamMetadata(ctx context.Context, streamID, patch string) (*model.StreamMetadata, error)
 	AddNewUser(ctx context.Context, userInfo *model.StreamMemberParams) error
+	GetUserSnapshot(ctx context.Context, userID string) (*model.UserSnapshot, error)
+	UpdateUserLastOnline(ctx context.Context, userID string) error
 	AddUserSubscriptions(ctx context.Context, subscriptions []model.UserSubscription) error
+	RemoveUserSubscription(ctx context.Context, userID, channel string) error
+	GetEventsAfter(ctx context.Context, userID string, streamIDs []string, after model.EventCursor, limit int) ([]model.Event, error)
+	GetLastEventCursor(ctx context.Context) (model.EventCursor, error)
+	GetStreamPersonalChannels(ctx context.Context, streamID string, userIDs []string) ([]model.PersonalChannelMember, error)
 	SaveMessage(ctx context.Context, message *model.Message) error
+	GetMessage(ctx context.Context, messageID string) (*model.Message, error)
+	UpdateMessageContent(ctx context.Context, messageID, content string) (time.Time, error)
+	HideMessage(ctx context.Context, messageID, userID string) error
+	DeleteMessage(ctx context.Context, messageID, deletedBy string) (time.Time, error)
+	MarkStreamRead(ctx context.Context, streamID, userID, messageID string) (*time.Time, error)
+	// GetStreamReadMarks возвращает отметки, дошедшие как минимум до since
+	GetStreamReadMarks(ctx context.Context, streamID string, since model.MessageCursor) (model.ReadMarkList, error)
 	IsStreamMember(ctx context.Context, streamID, userID string) (bool, error)
+	GetStreamMembership(ctx context.Context, streamID, userID string) (*model.StreamMembership, error)
+	GetStreamType(ctx context.Context, streamID string) (string, error)
+	GetStreams(ctx context.Context, requesterID, streamType string, cursor *model.StreamCursor, limit int) (*model.StreamPreviewList, error)
 	GetPrivateStreams(ctx context.Context, requesterID string) (*model.PrivateStreamPreviewList, error)
-	GetStreamRecentMessages(ctx context.Context, streamID string, offset string, limit int32) (*model.MessageList, error)
+	GetGroupStreams(ctx context.Context, requesterID string) (*model.GroupStreamPreviewList, error)
+	GetChannelStreams(ctx context.Context, requesterID string) (*model.ChannelStreamPreviewList, error)
+	GetStreamRecentMessages(ctx context.Context, streamID, userID string, cursor *model.MessageCursor, direction string, limit int32) (*model.MessageList, error)
+	GetStreamMessage(ctx context.Context, streamID, userID, messageID string) (*model.Message, error)
+	GetThreadMessages(ctx context.Context, streamID, rootID, userID string, cursor *model.MessageCursor, limit int32) (*model.MessageList, error)
 	GetUserActiveStreams(ctx context.Context, userID string) ([]string, error)
+	EnqueueOutbox(ctx context.Context, channel string, data interface{}) error
+	SaveAttachment(ctx context.Context, attachment *model.Attachment) error
+	GetAttachment(ctx context.Context, storageKey string) (*model.Attachment, error)
 
 	WithTx(ctx context.Context, cb func(ctx context.Context) error) error
 }
@@ -27,16 +59,34 @@ type UserClient interface {
 }
 
 type CetrifugeClient interface {
-	Publish(ctx context.Context, channel string, data model.Message) error
+	Publish(ctx context.Context, channel string, data interface{}) error
+	Broadcast(ctx context.Context, channels []string, data interface{}) error
+	PublishBatch(ctx context.Context, publications []model.ChannelPublication) error
+}
+
+// PresenceClient — server API Centrifugo: присутствие в каналах и управление подписками
+type PresenceClient interface {
+	Presence(ctx context.Context, channel string) (map[string]model.CentrifugoClientInfo, error)
+	PresenceStatsBatch(ctx context.Context, channels []string) ([]*model.PresenceStats, error)
+	Unsubscribe(ctx context.Context, user, channel string) error
 }
 
 type Validator interface {
 	ValidateCreateStream(req *api.CreateStreamRequest, creatorID string) error
 	ValidateSendMessage(req *api.SendMessageRequest) error
+	ValidateEditMessage(req *api.EditMessageRequest) error
+	ValidateUpdateStream(req *api.UpdateStreamRequest) error
+}
+
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (string, error)
 }
 
 type JWTGenerator interface {
-	GenerateConnectToken(userID string) (string, int64, error)
+	GenerateConnectToken(userID string, channels []string) (string, int64, error)
 	GenerateSubscribeToken(userID, streamID string) (string, int64, error)
 	ValidateConnectToken(tokenString string) (*model.CentrifugoConnectClaims, error)
 	ValidateSubscribeToken(tokenString string) (*model.CentrifugoSubscribeClaims, error)
@@ -1,43 +1,110 @@
 package rest
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"mime"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	logger_lib "github.com/s21platform/logger-lib"
 
+	"github.com/s21platform/chat-service/internal/client/blobstore"
 	"github.com/s21platform/chat-service/internal/config"
 	api "github.com/s21platform/chat-service/internal/generated"
 	"github.com/s21platform/chat-service/internal/model"
+	"github.com/s21platform/chat-service/internal/pkg/ratelimit"
+	"github.com/s21platform/chat-service/internal/pkg/thumbnail"
 	"github.com/s21platform/chat-service/internal/pkg/tx"
 )
 
+var (
+	errNotStreamMember   = errors.New("user is not a member of this stream")
+	errPostingNotAllowed = errors.New("user is not allowed to post to this stream")
+	errStreamNotFound    = errors.New("stream not found")
+	errWrongStreamType   = errors.New("operation is not supported for this stream type")
+	errMessageNotFound   = errors.New("message not found")
+	errNotMessageSender  = errors.New("only the sender can modify this message")
+	errEditWindowExpired = errors.New("message edit window has expired")
+	errInvalidReference  = errors.New("referenced message must exist in the same stream and not be deleted")
+	errInvalidAttachment = errors.New("media must reference an attachment uploaded by the sender")
+	errUploadTooLarge    = errors.New("file exceeds the maximum upload size")
+	errNotUploaded       = errors.New("attachment has not been uploaded")
+	errMimeNotAllowed    = errors.New("mime type is not allowed")
+	errNotStreamAdmin    = errors.New("only the owner or an admin can manage members")
+	errMemberNotFound    = errors.New("member not found")
+	errOwnerCannotLeave  = errors.New("owner cannot leave the stream")
+	errNotStreamOwner    = errors.New("only the owner can transfer ownership")
+)
+
+const (
+	defaultContextLimit = 10
+	maxContextLimit     = 50
+
+	defaultStreamsLimit = 20
+	maxStreamsLimit     = 100
+
+	defaultMessagesLimit = 20
+	maxMessagesLimit     = 100
+
+	multipartOverhead = 1 << 20
+	thumbnailSuffix   = "_thumb"
+
+	// коды ответов Centrifugo proxy: ошибки приложения в диапазоне 400-1999, дисконнект без переподключения 4500-4999
+	proxyPermissionDeniedCode   = 403
+	proxyUnauthorizedDisconnect = 4501
+)
+
 type Handler struct {
 	repository       DBRepo
 	userClient       UserClient
 	centrifugeClient CetrifugeClient
+	presenceClient   PresenceClient
 	validator        Validator
 	jwtGenerator     JWTGenerator
+	blobStore        BlobStore
+	editWindow       time.Duration
+	storage          config.Storage
+	proxyTTL         time.Duration
+	sse              config.SSE
+	typingLimiter    *ratelimit.Limiter
+	typingTTL        time.Duration
 }
 
 func New(
 	repo DBRepo,
 	userClient UserClient,
 	centrifugeClient CetrifugeClient,
+	presenceClient PresenceClient,
 	validator Validator,
 	jwtGenerator JWTGenerator,
+	blobStore BlobStore,
+	cfg *config.Config,
 ) *Handler {
 	return &Handler{
 		repository:       repo,
 		userClient:       userClient,
 		centrifugeClient: centrifugeClient,
+		presenceClient:   presenceClient,
 		validator:        validator,
 		jwtGenerator:     jwtGenerator,
+		blobStore:        blobStore,
+		editWindow:       cfg.Messages.EditWindow,
+		storage:          cfg.Storage,
+		proxyTTL:         cfg.Centrifuge.ProxyTTL,
+		sse:              cfg.SSE,
+		typingLimiter:    ratelimit.New(cfg.Typing.Interval),
+		typingTTL:        cfg.Typing.TTL,
 	}
 }
 
@@ -59,14 +126,40 @@ func (h *Handler) CreateStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// UUID в другом регистре или записи не должны давать другого участника и другую пару личного стрима
+	creatorID, err := canonicalUUID(creatorID)
+	if err != nil {
+		h.writeError(w, "invalid creator id", http.StatusBadRequest)
+		return
+	}
+
+	for i := range req.Users {
+		if strings.TrimSpace(req.Users[i].Id) == "" {
+			continue
+		}
+
+		req.Users[i].Id, err = canonicalUUID(req.Users[i].Id)
+		if err != nil {
+			h.writeError(w, "invalid user id", http.StatusBadRequest)
+			return
+		}
+	}
+
 	if err := h.validator.ValidateCreateStream(&req, creatorID); err != nil {
 		logger.Error(fmt.Sprintf("stream validation failed: %v", err))
 		h.writeError(w, fmt.Sprintf("stream validation failed: %v", err), http.StatusBadRequest)
 		return
 	}
 
+	chatMetadata, err := h.streamMetadata(&req)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to build stream metadata: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to build stream metadata: %v", err), http.StatusInternalServerError)
+		return
+	}
+
 	var streamID string
-	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+	err = tx.TxExecute(r.Context(), func(ctx context.Context) error {
 		allUserIDs := []string{creatorID}
 		for _, user := range req.Users {
 			if user.Id != "" && user.Id != creatorID {
@@ -89,16 +182,31 @@ func (h *Handler) CreateStream(w http.ResponseWriter, r *http.Request) {
 		}
 
 		var err error
-		streamID, err = h.repository.CreateStream(ctx, req.Type, req.ChatMetadata, creatorID)
-		if err != nil {
-			logger.Error(fmt.Sprintf("failed to create stream: %v", err))
-			return err
+		if req.Type == model.PrivateStreamType {
+			var created bool
+			streamID, created, err = h.repository.GetOrCreatePrivateStream(ctx, chatMetadata, creatorID, model.PrivatePair(uuid.MustParse(allUserIDs[0]), uuid.MustParse(allUserIDs[1])))
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to get or create private stream: %v", err))
+				return err
+			}
+
+			// личный стрим с этим пользователем уже есть — участники и подписки на месте
+			if !created {
+				return nil
+			}
+		} else {
+			streamID, err = h.repository.CreateStream(ctx, req.Type, chatMetadata, creatorID)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to create stream: %v", err))
+				return err
+			}
 		}
 
 		var members []model.StreamMember
 		members = append(members, model.StreamMember{
 			UserID:   creatorID,
 			Metadata: req.CreatorMetadata,
+			Role:     model.OwnerRole,
 		})
 
 		for _, user := range req.Users {
@@ -110,6 +218,7 @@ func (h *Handler) CreateStream(w http.ResponseWriter, r *http.Request) {
 				members = append(members, model.StreamMember{
 					UserID:   user.Id,
 					Metadata: metadata,
+					Role:     model.MemberRole,
 				})
 			}
 		}
@@ -125,6 +234,9 @@ func (h *Handler) CreateStream(w http.ResponseWriter, r *http.Request) {
 			subscriptions = append(subscriptions, model.UserSubscription{
 				UserID:  member.UserID,
 				Channel: streamID,
+			}, model.UserSubscription{
+				UserID:  member.UserID,
+				Channel: model.PersonalChannel(member.UserID),
 			})
 		}
 
@@ -134,6 +246,20 @@ func (h *Handler) CreateStream(w http.ResponseWriter, r *http.Request) {
 			return err
 		}
 
+		creator, err := h.userSnapshot(ctx, creatorID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get creator snapshot: %v", err))
+			return fmt.Errorf("failed to get creator snapshot: %v", err)
+		}
+
+		for _, member := range members {
+			err = h.notifyStreamAdded(ctx, streamID, req.Type, member, *creator)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to enqueue stream added event: %v", err))
+				return err
+			}
+		}
+
 		return nil
 	})
 
@@ -150,6 +276,79 @@ func (h *Handler) CreateStream(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, response, http.StatusOK)
 }
 
+func (h *Handler) GetStreams(w http.ResponseWriter, r *http.Request, params api.GetStreamsParams) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetStreams")
+
+	requesterID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get requester id")
+		h.writeError(w, "failed to get requester id", http.StatusInternalServerError)
+		return
+	}
+
+	var streamType string
+	if params.Type != nil {
+		streamType = string(*params.Type)
+		switch streamType {
+		case model.PrivateStreamType, model.GroupStreamType, model.ChannelStreamType, model.CommentStreamType:
+		default:
+			logger.Error(fmt.Sprintf("invalid stream type: %s", streamType))
+			h.writeError(w, "invalid stream type", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var cursor *model.StreamCursor
+	if params.Cursor != nil && *params.Cursor != "" {
+		parsed, err := model.ParseStreamCursor(*params.Cursor)
+		if err != nil {
+			logger.Error(fmt.Sprintf("invalid cursor: %v", err))
+			h.writeError(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		cursor = &parsed
+	}
+
+	limit := defaultStreamsLimit
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = min(*params.Limit, maxStreamsLimit)
+	}
+
+	// запрашиваем на один стрим больше, чтобы понять, есть ли следующая страница
+	previews, err := h.repository.GetStreams(r.Context(), requesterID, streamType, cursor, limit+1)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get streams: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to get streams: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var nextCursor *string
+	if len(*previews) > limit {
+		*previews = (*previews)[:limit]
+		last, err := model.NewStreamCursor((*previews)[limit-1])
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to build cursor: %v", err))
+			h.writeError(w, fmt.Sprintf("failed to build cursor: %v", err), http.StatusInternalServerError)
+			return
+		}
+		encoded := last.Encode()
+		nextCursor = &encoded
+	}
+
+	streams := make([]api.StreamPreview, len(*previews))
+	for i, stream := range *previews {
+		streams[i] = toAPIStreamPreview(stream)
+	}
+
+	response := api.GetStreamsResponse{
+		Streams:    streams,
+		NextCursor: nextCursor,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
 func (h *Handler) GetPrivateStreams(w http.ResponseWriter, r *http.Request) {
 	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
 	logger.AddFuncName("GetPrivateStreams")
@@ -168,6 +367,8 @@ func (h *Handler) GetPrivateStreams(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	online := h.onlineUsers(r.Context(), *privateStreams)
+
 	streams := make([]api.PrivateStream, len(*privateStreams))
 	for i, stream := range *privateStreams {
 		var lastMessageTimestamp *string
@@ -176,12 +377,24 @@ func (h *Handler) GetPrivateStreams(w http.ResponseWriter, r *http.Request) {
 			lastMessageTimestamp = &timestamp
 		}
 
+		var lastOnline *string
+		if stream.CompanionLastOnline != nil {
+			timestamp := stream.CompanionLastOnline.Format(time.RFC3339)
+			lastOnline = &timestamp
+		}
+
 		streams[i] = api.PrivateStream{
 			StreamId:             stream.StreamID,
 			LastMessageContent:   stream.LastMessageContent,
 			StreamName:           stream.StreamName,
 			AvatarUrl:            &stream.AvatarURL,
+			CompanionId:          stream.CompanionID,
+			Online:               online[i],
+			LastOnline:           lastOnline,
 			LastMessageTimestamp: lastMessageTimestamp,
+			UnreadCount:          stream.UnreadCount,
+			Muted:                stream.Muted,
+			NotifyLevel:          api.NotifyLevel(stream.NotifyLevel),
 		}
 	}
 
@@ -192,172 +405,252 @@ func (h *Handler) GetPrivateStreams(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, response, http.StatusOK)
 }
 
-func (h *Handler) GetStreamRecentMessages(w http.ResponseWriter, r *http.Request, streamId string, params api.GetStreamRecentMessagesParams) {
+func (h *Handler) GetPrivateStreamWith(w http.ResponseWriter, r *http.Request, userId string) {
 	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
-	logger.AddFuncName("GetStreamRecentMessages")
+	logger.AddFuncName("GetPrivateStreamWith")
 
 	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
 	if !ok {
-		logger.Error("failed to find uuid")
-		h.writeError(w, "failed to find uuid", http.StatusInternalServerError)
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
 		return
 	}
 
-	isMember, err := h.repository.IsStreamMember(r.Context(), streamId, userUUID)
+	userID, err := uuid.Parse(userUUID)
 	if err != nil {
-		logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
-		h.writeError(w, fmt.Sprintf("failed to check stream membership: %v", err), http.StatusInternalServerError)
+		h.writeError(w, "invalid requester id", http.StatusBadRequest)
 		return
 	}
 
-	if !isMember {
-		logger.Error("user is not a member of the stream")
-		h.writeError(w, "user is not a member of the stream", http.StatusForbidden)
+	companionID, err := uuid.Parse(userId)
+	if err != nil {
+		h.writeError(w, "invalid user id", http.StatusBadRequest)
 		return
 	}
 
-	offset := ""
-	if params.Offset != nil {
-		offset = *params.Offset
+	streamID, err := h.repository.GetPrivateStreamByPair(r.Context(), model.PrivatePair(userID, companionID))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get private stream: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to get private stream: %v", err), http.StatusInternalServerError)
+		return
 	}
 
-	limit := int32(20)
-	if params.Limit != nil {
-		limit = int32(*params.Limit)
+	if streamID == "" {
+		h.writeError(w, errStreamNotFound.Error(), http.StatusNotFound)
+		return
 	}
 
-	messages, err := h.repository.GetStreamRecentMessages(r.Context(), streamId, offset, limit)
-	if err != nil {
-		logger.Error(fmt.Sprintf("failed to fetch messages: %v", err))
-		h.writeError(w, fmt.Sprintf("failed to fetch messages: %v", err), http.StatusInternalServerError)
-		return
+	response := api.GetPrivateStreamWithResponse{
+		StreamId: streamID,
 	}
 
-	apiMessages := make([]api.Message, len(*messages))
-	for i, msg := range *messages {
-		var updatedAt *string
-		if msg.UpdatedAt != nil {
-			timestamp := msg.UpdatedAt.Format(time.RFC3339)
-			updatedAt = &timestamp
-		}
+	h.writeJSON(w, response, http.StatusOK)
+}
 
-		var rootUuid *string
-		if msg.RootID != nil {
-			uuid := msg.RootID.String()
-			rootUuid = &uuid
-		}
+func (h *Handler) GetGroupStreams(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetGroupStreams")
+
+	requesterID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get requester id")
+		h.writeError(w, "failed to get requester id", http.StatusInternalServerError)
+		return
+	}
+
+	groupStreams, err := h.repository.GetGroupStreams(r.Context(), requesterID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get group streams: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to get group streams: %v", err), http.StatusInternalServerError)
+		return
+	}
 
-		var parentUuid *string
-		if msg.ParentID != nil {
-			uuid := msg.ParentID.String()
-			parentUuid = &uuid
+	streams := make([]api.GroupStream, len(*groupStreams))
+	for i, stream := range *groupStreams {
+		var lastMessageTimestamp *string
+		if stream.LastMessageTimestamp != nil {
+			timestamp := stream.LastMessageTimestamp.Format(time.RFC3339)
+			lastMessageTimestamp = &timestamp
 		}
 
-		apiMessages[i] = api.Message{
-			Uuid:       msg.ID.String(),
-			Content:    msg.Content,
-			SentAt:     msg.SentAt.Format(time.RFC3339),
-			UpdatedAt:  updatedAt,
-			RootUuid:   rootUuid,
-			ParentUuid: parentUuid,
+		streams[i] = api.GroupStream{
+			StreamId:             stream.StreamID,
+			LastMessageContent:   stream.LastMessageContent,
+			StreamName:           stream.StreamName,
+			AvatarUrl:            &stream.AvatarURL,
+			LastMessageTimestamp: lastMessageTimestamp,
+			UnreadCount:          stream.UnreadCount,
+			Muted:                stream.Muted,
+			NotifyLevel:          api.NotifyLevel(stream.NotifyLevel),
 		}
 	}
 
-	response := api.GetStreamRecentMessagesResponse{
-		Messages: apiMessages,
+	response := api.GetGroupStreamsResponse{
+		Streams: streams,
 	}
 
 	h.writeJSON(w, response, http.StatusOK)
 }
 
-func (h *Handler) SendMessage(w http.ResponseWriter, r *http.Request, streamId string) {
+func (h *Handler) GetChannelStreams(w http.ResponseWriter, r *http.Request) {
 	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
-	logger.AddFuncName("SendMessage")
+	logger.AddFuncName("GetChannelStreams")
 
-	var req api.SendMessageRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
-		h.writeError(w, "invalid request body", http.StatusBadRequest)
+	requesterID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get requester id")
+		h.writeError(w, "failed to get requester id", http.StatusInternalServerError)
 		return
 	}
 
-	senderID, ok := r.Context().Value(config.KeyUUID).(string)
-	if !ok {
-		logger.Error("failed to get sender ID")
-		h.writeError(w, "failed to get sender ID", http.StatusInternalServerError)
+	channelStreams, err := h.repository.GetChannelStreams(r.Context(), requesterID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get channel streams: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to get channel streams: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	if err := h.validator.ValidateSendMessage(&req); err != nil {
-		logger.Error(fmt.Sprintf("message validation failed: %v", err))
-		h.writeError(w, fmt.Sprintf("message validation failed: %v", err), http.StatusBadRequest)
+	streams := make([]api.ChannelStream, len(*channelStreams))
+	for i, stream := range *channelStreams {
+		var lastMessageTimestamp *string
+		if stream.LastMessageTimestamp != nil {
+			timestamp := stream.LastMessageTimestamp.Format(time.RFC3339)
+			lastMessageTimestamp = &timestamp
+		}
+
+		streams[i] = api.ChannelStream{
+			StreamId:             stream.StreamID,
+			LastMessageContent:   stream.LastMessageContent,
+			StreamName:           stream.StreamName,
+			AvatarUrl:            &stream.AvatarURL,
+			LastMessageTimestamp: lastMessageTimestamp,
+			UnreadCount:          stream.UnreadCount,
+			Role:                 stream.Role,
+			Muted:                stream.Muted,
+			NotifyLevel:          api.NotifyLevel(stream.NotifyLevel),
+		}
+	}
+
+	response := api.GetChannelStreamsResponse{
+		Streams: streams,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) SubscribeToStream(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("SubscribeToStream")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
 		return
 	}
 
-	var message model.Message
 	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
-		isMember, err := h.repository.IsStreamMember(ctx, streamId, senderID)
+		streamType, err := h.repository.GetStreamType(ctx, streamId)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get stream type: %v", err))
+			return fmt.Errorf("failed to get stream type: %v", err)
+		}
+
+		if streamType == "" {
+			return errStreamNotFound
+		}
+
+		if streamType != model.ChannelStreamType && streamType != model.CommentStreamType {
+			return errWrongStreamType
+		}
+
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
 		if err != nil {
 			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
 			return fmt.Errorf("failed to check stream membership: %v", err)
 		}
 
-		if !isMember {
-			logger.Error(fmt.Sprintf("user %s is not a member of stream %s", senderID, streamId))
-			return fmt.Errorf("user is not a member of this stream")
+		if membership != nil {
+			return nil
 		}
 
-		message = model.Message{
-			ID:       uuid.New(),
-			StreamID: uuid.MustParse(streamId),
-			SenderID: uuid.MustParse(senderID),
-			Type:     req.MessageType,
-			Content:  req.Content,
-			SentAt:   time.Now(),
+		userInfo, err := h.userClient.GetUserInfoByUUID(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get user info for %s: %v", userUUID, err))
+			return fmt.Errorf("failed to get user info for %s: %v", userUUID, err)
 		}
 
-		if req.ParentId != nil && *req.ParentId != "" {
-			parentUUID := uuid.MustParse(*req.ParentId)
-			message.ParentID = &parentUUID
+		err = h.repository.AddNewUser(ctx, userInfo)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to add user %s to users table: %v", userUUID, err))
+			return fmt.Errorf("failed to add user %s to users table: %v", userUUID, err)
 		}
 
-		if req.RootId != nil && *req.RootId != "" {
-			rootUUID := uuid.MustParse(*req.RootId)
-			message.RootID = &rootUUID
+		member := model.StreamMember{
+			UserID:   userUUID,
+			Metadata: "{}",
+			Role:     model.MemberRole,
 		}
 
-		err = h.repository.SaveMessage(ctx, &message)
+		err = h.repository.AddStreamMembers(ctx, streamId, []model.StreamMember{member})
 		if err != nil {
-			logger.Error(fmt.Sprintf("failed to save message: %v", err))
-			return fmt.Errorf("failed to save message: %v", err)
+			logger.Error(fmt.Sprintf("failed to add stream member: %v", err))
+			return err
+		}
+
+		err = h.repository.AddUserSubscriptions(ctx, []model.UserSubscription{
+			{
+				UserID:  userUUID,
+				Channel: streamId,
+			},
+			{
+				UserID:  userUUID,
+				Channel: model.PersonalChannel(userUUID),
+			},
+		})
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to create subscription: %v", err))
+			return err
+		}
+
+		actor, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get actor snapshot: %v", err))
+			return fmt.Errorf("failed to get actor snapshot: %v", err)
+		}
+
+		err = h.notifyStreamAdded(ctx, streamId, streamType, member, *actor)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to enqueue stream added event: %v", err))
+			return err
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		logger.Error(fmt.Sprintf("failed to send message transaction: %v", err))
-		h.writeError(w, fmt.Sprintf("failed to send message: %v", err), http.StatusInternalServerError)
+		logger.Error(fmt.Sprintf("failed to subscribe to stream: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to subscribe to stream: %v", err), errorStatus(err))
 		return
 	}
 
-	channel := message.StreamID.String()
-	err = h.centrifugeClient.Publish(r.Context(), channel, message)
-	if err != nil {
-		logger.Error(fmt.Sprintf("failed to publish message to stream: %v", err))
-	}
-
-	response := api.SendMessageResponse{
-		MessageId: message.ID.String(),
-		SentAt:    message.SentAt.Format(time.RFC3339),
+	response := api.SubscribeToStreamResponse{
+		StreamId: streamId,
 	}
 
 	h.writeJSON(w, response, http.StatusOK)
 }
 
-func (h *Handler) GetConnectAccessToken(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) AddStreamMembers(w http.ResponseWriter, r *http.Request, streamId string) {
 	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
-	logger.AddFuncName("GetConnectAccessToken")
+	logger.AddFuncName("AddStreamMembers")
+
+	var req api.AddStreamMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
 
 	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
 	if !ok {
@@ -366,68 +659,222 @@ func (h *Handler) GetConnectAccessToken(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	token, expiresAt, err := h.jwtGenerator.GenerateConnectToken(userUUID)
-	if err != nil {
-		logger.Error(fmt.Sprintf("failed to generate access token: %v", err))
-		h.writeError(w, fmt.Sprintf("failed to generate access token: %v", err), http.StatusInternalServerError)
+	if len(req.Users) == 0 {
+		h.writeError(w, "users are required", http.StatusBadRequest)
 		return
 	}
 
-	logger.Info(fmt.Sprintf("generated access token for user %s", userUUID))
-
-	response := api.GetConnectAccessTokenResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
+	for _, user := range req.Users {
+		if _, err := uuid.Parse(user.Id); err != nil {
+			h.writeError(w, fmt.Sprintf("invalid user id: %s", user.Id), http.StatusBadRequest)
+			return
+		}
 	}
 
-	h.writeJSON(w, response, http.StatusOK)
-}
+	added := make([]string, 0, len(req.Users))
+	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
 
-func (h *Handler) GetStreamSubscribeToken(w http.ResponseWriter, r *http.Request, streamId string) {
-	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
-	logger.AddFuncName("GetStreamSubscribeToken")
+		if membership == nil {
+			return errNotStreamMember
+		}
 
-	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
-	if !ok {
-		logger.Error("failed to get user UUID")
-		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
-		return
-	}
+		if membership.StreamType != model.GroupStreamType {
+			return errWrongStreamType
+		}
 
-	isMember, err := h.repository.IsStreamMember(r.Context(), streamId, userUUID)
-	if err != nil {
-		logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
-		h.writeError(w, fmt.Sprintf("failed to check stream membership: %v", err), http.StatusInternalServerError)
-		return
-	}
+		if !membership.CanInvite() {
+			return errNotStreamAdmin
+		}
 
-	if !isMember {
-		logger.Error("user is not a member of the stream")
-		h.writeError(w, "user is not a member of the stream", http.StatusForbidden)
+		var members []model.StreamMember
+		seen := map[string]bool{userUUID: true}
+		for _, user := range req.Users {
+			if seen[user.Id] {
+				continue
+			}
+			seen[user.Id] = true
+
+			isMember, err := h.repository.IsStreamMember(ctx, streamId, user.Id)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+				return fmt.Errorf("failed to check stream membership: %v", err)
+			}
+
+			if isMember {
+				continue
+			}
+
+			userInfo, err := h.userClient.GetUserInfoByUUID(ctx, user.Id)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to get user info for %s: %v", user.Id, err))
+				return fmt.Errorf("failed to get user info for %s: %v", user.Id, err)
+			}
+
+			err = h.repository.AddNewUser(ctx, userInfo)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to add user %s to users table: %v", user.Id, err))
+				return fmt.Errorf("failed to add user %s to users table: %v", user.Id, err)
+			}
+
+			metadata := "{}"
+			if user.Metadata != nil {
+				metadata = *user.Metadata
+			}
+			members = append(members, model.StreamMember{
+				UserID:   user.Id,
+				Metadata: metadata,
+				Role:     model.MemberRole,
+			})
+		}
+
+		if len(members) == 0 {
+			return nil
+		}
+
+		err = h.repository.AddStreamMembers(ctx, streamId, members)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to add stream members: %v", err))
+			return err
+		}
+
+		var subscriptions []model.UserSubscription
+		for _, member := range members {
+			subscriptions = append(subscriptions, model.UserSubscription{
+				UserID:  member.UserID,
+				Channel: streamId,
+			}, model.UserSubscription{
+				UserID:  member.UserID,
+				Channel: model.PersonalChannel(member.UserID),
+			})
+		}
+
+		err = h.repository.AddUserSubscriptions(ctx, subscriptions)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to create subscriptions: %v", err))
+			return err
+		}
+
+		actor, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get actor snapshot: %v", err))
+			return fmt.Errorf("failed to get actor snapshot: %v", err)
+		}
+
+		for _, member := range members {
+			err = h.notifyStreamAdded(ctx, streamId, membership.StreamType, member, *actor)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to enqueue stream added event: %v", err))
+				return err
+			}
+
+			err = h.notifyMembership(ctx, model.MemberJoinedEvent, streamId, member.UserID, *actor)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to enqueue member joined event: %v", err))
+				return err
+			}
+
+			added = append(added, member.UserID)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to add stream members: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to add stream members: %v", err), errorStatus(err))
 		return
 	}
 
-	token, expiresAt, err := h.jwtGenerator.GenerateSubscribeToken(userUUID, streamId)
+	response := api.AddStreamMembersResponse{
+		Added: added,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) RemoveStreamMember(w http.ResponseWriter, r *http.Request, streamId string, userId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("RemoveStreamMember")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := uuid.Parse(userId); err != nil {
+		h.writeError(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	if userId == userUUID {
+		h.writeError(w, "use the leave endpoint to leave the stream", http.StatusBadRequest)
+		return
+	}
+
+	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if membership == nil {
+			return errNotStreamMember
+		}
+
+		if membership.StreamType != model.GroupStreamType && membership.StreamType != model.ChannelStreamType {
+			return errWrongStreamType
+		}
+
+		target, err := h.repository.GetStreamMembership(ctx, streamId, userId)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if target == nil {
+			return errMemberNotFound
+		}
+
+		if !membership.CanManageMember(target) {
+			return errNotStreamAdmin
+		}
+
+		actor, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get actor snapshot: %v", err))
+			return fmt.Errorf("failed to get actor snapshot: %v", err)
+		}
+
+		return h.leaveStream(ctx, streamId, userId, *actor)
+	})
+
 	if err != nil {
-		logger.Error(fmt.Sprintf("failed to generate subscribe token: %v", err))
-		h.writeError(w, fmt.Sprintf("failed to generate subscribe token: %v", err), http.StatusInternalServerError)
+		logger.Error(fmt.Sprintf("failed to remove stream member: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to remove stream member: %v", err), errorStatus(err))
 		return
 	}
 
-	logger.Info(fmt.Sprintf("generated subscribe token for user %s, stream %s", userUUID, streamId))
+	h.unsubscribeFromStream(r.Context(), userId, streamId)
 
-	response := api.GetStreamSubscribeTokenResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		Channel:   streamId,
+	response := api.StreamMemberResponse{
+		StreamId: streamId,
+		UserId:   userId,
 	}
 
 	h.writeJSON(w, response, http.StatusOK)
 }
 
-func (h *Handler) GetUserActiveStreams(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) LeaveStream(w http.ResponseWriter, r *http.Request, streamId string) {
 	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
-	logger.AddFuncName("GetUserActiveStreams")
+	logger.AddFuncName("LeaveStream")
 
 	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
 	if !ok {
@@ -436,25 +883,55 @@ func (h *Handler) GetUserActiveStreams(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	streamIDs, err := h.repository.GetUserActiveStreams(r.Context(), userUUID)
+	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if membership == nil {
+			return errNotStreamMember
+		}
+
+		if membership.StreamType == model.PrivateStreamType {
+			return errWrongStreamType
+		}
+
+		if membership.Role == model.OwnerRole {
+			return errOwnerCannotLeave
+		}
+
+		actor, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get actor snapshot: %v", err))
+			return fmt.Errorf("failed to get actor snapshot: %v", err)
+		}
+
+		return h.leaveStream(ctx, streamId, userUUID, *actor)
+	})
+
 	if err != nil {
-		logger.Error(fmt.Sprintf("failed to get user active streams: %v", err))
-		h.writeError(w, fmt.Sprintf("failed to get user active streams: %v", err), http.StatusInternalServerError)
+		logger.Error(fmt.Sprintf("failed to leave stream: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to leave stream: %v", err), errorStatus(err))
 		return
 	}
 
-	response := api.GetUserActiveStreamsResponse{
-		StreamIds: streamIDs,
+	h.unsubscribeFromStream(r.Context(), userUUID, streamId)
+
+	response := api.StreamMemberResponse{
+		StreamId: streamId,
+		UserId:   userUUID,
 	}
 
 	h.writeJSON(w, response, http.StatusOK)
 }
 
-func (h *Handler) GetBatchSubscribeTokens(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateStream(w http.ResponseWriter, r *http.Request, streamId string) {
 	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
-	logger.AddFuncName("GetBatchSubscribeTokens")
+	logger.AddFuncName("UpdateStream")
 
-	var req api.GetBatchSubscribeTokensRequest
+	var req api.UpdateStreamRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
 		h.writeError(w, "invalid request body", http.StatusBadRequest)
@@ -468,53 +945,2476 @@ func (h *Handler) GetBatchSubscribeTokens(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	var subscriptions []api.StreamSubscription
+	if err := h.validator.ValidateUpdateStream(&req); err != nil {
+		logger.Error(fmt.Sprintf("stream validation failed: %v", err))
+		h.writeError(w, fmt.Sprintf("stream validation failed: %v", err), http.StatusBadRequest)
+		return
+	}
 
-	for _, streamID := range req.StreamIds {
-		isMember, err := h.repository.IsStreamMember(r.Context(), streamID, userUUID)
+	patch := make(map[string]string)
+	if req.Title != nil {
+		patch["title"] = strings.TrimSpace(*req.Title)
+	}
+	if req.AvatarUrl != nil {
+		patch["avatar_url"] = *req.AvatarUrl
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to build stream metadata: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to build stream metadata: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var metadata *model.StreamMetadata
+	err = tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
 		if err != nil {
-			logger.Error(fmt.Sprintf("failed to check stream membership for %s: %v", streamID, err))
-			continue
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
 		}
 
-		if !isMember {
-			logger.Warn(fmt.Sprintf("user %s is not a member of stream %s, skipping", userUUID, streamID))
-			continue
+		if membership == nil {
+			return errNotStreamMember
 		}
 
-		token, expiresAt, err := h.jwtGenerator.GenerateSubscribeToken(userUUID, streamID)
+		if membership.StreamType != model.GroupStreamType && membership.StreamType != model.ChannelStreamType {
+			return errWrongStreamType
+		}
+
+		if !membership.CanEditStream() {
+			return errNotStreamAdmin
+		}
+
+		metadata, err = h.repository.UpdateStreamMetadata(ctx, streamId, string(data))
 		if err != nil {
-			logger.Error(fmt.Sprintf("failed to generate subscribe token for stream %s: %v", streamID, err))
-			continue
+			logger.Error(fmt.Sprintf("failed to update stream metadata: %v", err))
+			return err
 		}
 
-		subscriptions = append(subscriptions, api.StreamSubscription{
-			StreamId:  streamID,
-			Token:     token,
-			ExpiresAt: expiresAt,
-			Channel:   streamID,
+		if metadata == nil {
+			return errStreamNotFound
+		}
+
+		actor, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get actor snapshot: %v", err))
+			return fmt.Errorf("failed to get actor snapshot: %v", err)
+		}
+
+		event := model.NewEventEnvelope(model.StreamEditedEvent, model.StreamEditedEventPayload{
+			StreamID: uuid.MustParse(streamId),
+			Metadata: *metadata,
+			Actor:    *actor,
 		})
+		err = h.repository.EnqueueOutbox(ctx, streamId, event)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to enqueue stream edited event: %v", err))
+			return fmt.Errorf("failed to enqueue stream edited event: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to update stream: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to update stream: %v", err), errorStatus(err))
+		return
 	}
 
-	response := api.GetBatchSubscribeTokensResponse{
-		Subscriptions: subscriptions,
+	response := api.UpdateStreamResponse{
+		StreamId: streamId,
+		Title:    metadata.Title,
+	}
+	if metadata.AvatarURL != "" {
+		response.AvatarUrl = &metadata.AvatarURL
 	}
 
 	h.writeJSON(w, response, http.StatusOK)
 }
 
-// ----------------------------- helpers -----------------------------
+func (h *Handler) SetStreamMemberRole(w http.ResponseWriter, r *http.Request, streamId string, userId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("SetStreamMemberRole")
 
-func (h *Handler) writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	var req api.SetStreamMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	role := string(req.Role)
+	if !model.IsValidRole(role) || role == model.OwnerRole {
+		h.writeError(w, fmt.Sprintf("unsupported role: %s", role), http.StatusBadRequest)
+		return
+	}
+
+	if userId == userUUID {
+		h.writeError(w, "cannot change own role", http.StatusBadRequest)
+		return
+	}
+
+	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if membership == nil {
+			return errNotStreamMember
+		}
+
+		if membership.StreamType != model.GroupStreamType && membership.StreamType != model.ChannelStreamType {
+			return errWrongStreamType
+		}
+
+		target, err := h.repository.GetStreamMembership(ctx, streamId, userId)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if target == nil {
+			return errMemberNotFound
+		}
+
+		if !membership.CanManageMember(target) || !membership.CanAssignRole(role) {
+			return errNotStreamAdmin
+		}
+
+		if target.Role == role {
+			return nil
+		}
+
+		actor, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get actor snapshot: %v", err))
+			return fmt.Errorf("failed to get actor snapshot: %v", err)
+		}
+
+		return h.changeMemberRole(ctx, streamId, userId, role, *actor)
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to set member role: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to set member role: %v", err), errorStatus(err))
+		return
 	}
+
+	response := api.StreamMemberRoleResponse{
+		StreamId: streamId,
+		UserId:   userId,
+		Role:     role,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
 }
 
-func (h *Handler) writeError(w http.ResponseWriter, message string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	_ = json.NewEncoder(w).Encode(api.Error{Error: message})
+func (h *Handler) TransferStreamOwnership(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("TransferStreamOwnership")
+
+	var req api.TransferStreamOwnershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := uuid.Parse(req.UserId); err != nil || req.UserId == userUUID {
+		h.writeError(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if membership == nil {
+			return errNotStreamMember
+		}
+
+		if membership.StreamType != model.GroupStreamType && membership.StreamType != model.ChannelStreamType {
+			return errWrongStreamType
+		}
+
+		if membership.Role != model.OwnerRole {
+			return errNotStreamOwner
+		}
+
+		target, err := h.repository.GetStreamMembership(ctx, streamId, req.UserId)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if target == nil {
+			return errMemberNotFound
+		}
+
+		actor, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get actor snapshot: %v", err))
+			return fmt.Errorf("failed to get actor snapshot: %v", err)
+		}
+
+		// в стриме всегда ровно один owner: прежний становится admin
+		err = h.changeMemberRole(ctx, streamId, userUUID, model.AdminRole, *actor)
+		if err != nil {
+			return err
+		}
+
+		return h.changeMemberRole(ctx, streamId, req.UserId, model.OwnerRole, *actor)
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to transfer stream ownership: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to transfer stream ownership: %v", err), errorStatus(err))
+		return
+	}
+
+	response := api.StreamMemberRoleResponse{
+		StreamId: streamId,
+		UserId:   req.UserId,
+		Role:     model.OwnerRole,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) UpdateStreamNotifications(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("UpdateStreamNotifications")
+
+	var req api.UpdateStreamNotificationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	level := string(req.Level)
+	if !model.IsValidNotifyLevel(level) {
+		h.writeError(w, fmt.Sprintf("unsupported notify level: %s", level), http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.repository.UpdateNotifyLevel(r.Context(), streamId, userUUID, level)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to update notify level: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to update notify level: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if settings == nil {
+		h.writeError(w, errNotStreamMember.Error(), http.StatusForbidden)
+		return
+	}
+
+	h.writeJSON(w, toAPIStreamNotifications(streamId, *settings, time.Now()), http.StatusOK)
+}
+
+func (h *Handler) MuteStream(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("MuteStream")
+
+	// тело необязательно: без until стрим заглушается навсегда
+	var req api.MuteStreamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	var until *time.Time
+	if req.Until != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.Until)
+		if err != nil {
+			h.writeError(w, "invalid until", http.StatusBadRequest)
+			return
+		}
+
+		if !parsed.After(time.Now()) {
+			h.writeError(w, "until must be in the future", http.StatusBadRequest)
+			return
+		}
+
+		parsed = parsed.UTC()
+		until = &parsed
+	}
+
+	settings, err := h.repository.UpdateStreamMute(r.Context(), streamId, userUUID, true, until)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to mute stream: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to mute stream: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if settings == nil {
+		h.writeError(w, errNotStreamMember.Error(), http.StatusForbidden)
+		return
+	}
+
+	h.writeJSON(w, toAPIStreamNotifications(streamId, *settings, time.Now()), http.StatusOK)
+}
+
+func (h *Handler) UnmuteStream(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("UnmuteStream")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	settings, err := h.repository.UpdateStreamMute(r.Context(), streamId, userUUID, false, nil)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to unmute stream: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to unmute stream: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if settings == nil {
+		h.writeError(w, errNotStreamMember.Error(), http.StatusForbidden)
+		return
+	}
+
+	h.writeJSON(w, toAPIStreamNotifications(streamId, *settings, time.Now()), http.StatusOK)
+}
+
+func (h *Handler) GetStreamRecentMessages(w http.ResponseWriter, r *http.Request, streamId string, params api.GetStreamRecentMessagesParams) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetStreamRecentMessages")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to find uuid")
+		h.writeError(w, "failed to find uuid", http.StatusInternalServerError)
+		return
+	}
+
+	isMember, err := h.repository.IsStreamMember(r.Context(), streamId, userUUID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to check stream membership: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if !isMember {
+		logger.Error("user is not a member of the stream")
+		h.writeError(w, "user is not a member of the stream", http.StatusForbidden)
+		return
+	}
+
+	direction := model.BeforeCursorDirection
+	if params.Direction != nil {
+		direction = string(*params.Direction)
+	}
+
+	if direction != model.BeforeCursorDirection && direction != model.AfterCursorDirection {
+		logger.Error(fmt.Sprintf("invalid direction: %s", direction))
+		h.writeError(w, "invalid direction", http.StatusBadRequest)
+		return
+	}
+
+	var cursor *model.MessageCursor
+	switch {
+	case params.Cursor != nil && *params.Cursor != "":
+		parsed, err := model.ParseMessageCursor(*params.Cursor)
+		if err != nil {
+			logger.Error(fmt.Sprintf("invalid cursor: %v", err))
+			h.writeError(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		cursor = &parsed
+	case params.Offset != nil && *params.Offset != "":
+		parsed, err := model.CursorFromTimestamp(*params.Offset)
+		if err != nil {
+			logger.Error(fmt.Sprintf("invalid offset: %v", err))
+			h.writeError(w, "invalid offset", http.StatusBadRequest)
+			return
+		}
+		cursor = &parsed
+	}
+
+	limit := int32(defaultMessagesLimit)
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = int32(min(*params.Limit, maxMessagesLimit))
+	}
+
+	// запрашиваем на одно сообщение больше, чтобы понять, есть ли следующая страница
+	messages, err := h.repository.GetStreamRecentMessages(r.Context(), streamId, userUUID, cursor, direction, limit+1)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to fetch messages: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to fetch messages: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var nextCursor *string
+	if len(*messages) > int(limit) {
+		*messages = (*messages)[:limit]
+		encoded := model.NewMessageCursor((*messages)[limit-1]).Encode()
+		nextCursor = &encoded
+	}
+
+	readMarks, err := h.pageReadMarks(r.Context(), streamId, *messages)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to fetch read marks: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to fetch read marks: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	apiMessages := make([]api.Message, len(*messages))
+	for i, msg := range *messages {
+		apiMessages[i] = toAPIMessage(msg, readMarks)
+	}
+
+	response := api.GetStreamRecentMessagesResponse{
+		Messages:   apiMessages,
+		NextCursor: nextCursor,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) GetMessageContext(w http.ResponseWriter, r *http.Request, streamId string, messageId string, params api.GetMessageContextParams) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetMessageContext")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to find uuid")
+		h.writeError(w, "failed to find uuid", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := uuid.Parse(messageId); err != nil {
+		logger.Error(fmt.Sprintf("invalid message id: %v", err))
+		h.writeError(w, "invalid message id", http.StatusBadRequest)
+		return
+	}
+
+	limit := int32(defaultContextLimit)
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = int32(min(*params.Limit, maxContextLimit))
+	}
+
+	isMember, err := h.repository.IsStreamMember(r.Context(), streamId, userUUID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to check stream membership: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if !isMember {
+		logger.Error("user is not a member of the stream")
+		h.writeError(w, "user is not a member of the stream", http.StatusForbidden)
+		return
+	}
+
+	target, err := h.repository.GetStreamMessage(r.Context(), streamId, userUUID, messageId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get message: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to get message: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if target == nil {
+		logger.Error(fmt.Sprintf("message %s not found in stream %s", messageId, streamId))
+		h.writeError(w, errMessageNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	cursor := model.NewMessageCursor(*target)
+
+	// по одному лишнему сообщению с каждой стороны, чтобы понять, есть ли продолжение
+	before, err := h.repository.GetStreamRecentMessages(r.Context(), streamId, userUUID, &cursor, model.BeforeCursorDirection, limit+1)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to fetch messages before: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to fetch messages: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	after, err := h.repository.GetStreamRecentMessages(r.Context(), streamId, userUUID, &cursor, model.AfterCursorDirection, limit+1)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to fetch messages after: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to fetch messages: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var response api.GetMessageContextResponse
+
+	older := *before
+	if len(older) > int(limit) {
+		older = older[:limit]
+		response.HasOlder = true
+	}
+
+	newer := *after
+	if len(newer) > int(limit) {
+		newer = newer[:limit]
+		response.HasNewer = true
+	}
+
+	readMarks, err := h.pageReadMarks(r.Context(), streamId, older, model.MessageList{*target}, newer)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to fetch read marks: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to fetch read marks: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response.Messages = make([]api.Message, 0, len(older)+1+len(newer))
+	for i := len(older) - 1; i >= 0; i-- {
+		response.Messages = append(response.Messages, toAPIMessage(older[i], readMarks))
+	}
+	response.Messages = append(response.Messages, toAPIMessage(*target, readMarks))
+	for _, msg := range newer {
+		response.Messages = append(response.Messages, toAPIMessage(msg, readMarks))
+	}
+
+	// края окна: самое старое и самое новое сообщение
+	oldest, newest := *target, *target
+	if len(older) > 0 {
+		oldest = older[len(older)-1]
+	}
+	if len(newer) > 0 {
+		newest = newer[len(newer)-1]
+	}
+	response.BeforeCursor = model.NewMessageCursor(oldest).Encode()
+	response.AfterCursor = model.NewMessageCursor(newest).Encode()
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) SendMessage(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("SendMessage")
+
+	var req api.SendMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	senderID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get sender ID")
+		h.writeError(w, "failed to get sender ID", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.validator.ValidateSendMessage(&req); err != nil {
+		logger.Error(fmt.Sprintf("message validation failed: %v", err))
+		h.writeError(w, fmt.Sprintf("message validation failed: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var message model.Message
+	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, senderID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if membership == nil {
+			logger.Error(fmt.Sprintf("user %s is not a member of stream %s", senderID, streamId))
+			return errNotStreamMember
+		}
+
+		if !membership.CanPost() {
+			logger.Error(fmt.Sprintf("user %s is not allowed to post to stream %s", senderID, streamId))
+			return errPostingNotAllowed
+		}
+
+		message = model.Message{
+			ID:       uuid.New(),
+			StreamID: uuid.MustParse(streamId),
+			SenderID: uuid.MustParse(senderID),
+			Type:     req.MessageType,
+			Content:  req.Content,
+			Media:    toModelMedia(req.Media),
+		}
+
+		if message.Media != nil {
+			attachment, err := h.repository.GetAttachment(ctx, message.Media.StorageKey)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to get attachment: %v", err))
+				return fmt.Errorf("failed to get attachment: %v", err)
+			}
+
+			if attachment == nil || attachment.UploaderID.String() != senderID ||
+				attachment.MimeType != message.Media.MimeType || attachment.Size != message.Media.Size {
+				logger.Error(fmt.Sprintf("invalid attachment %s", message.Media.StorageKey))
+				return errInvalidAttachment
+			}
+
+			if attachment.ThumbnailKey != nil {
+				message.Media.ThumbnailKey = *attachment.ThumbnailKey
+			}
+		}
+
+		if req.RootId != nil && *req.RootId != "" {
+			root, err := h.referencedMessage(ctx, streamId, *req.RootId)
+			if err != nil {
+				logger.Error(fmt.Sprintf("invalid root message %s: %v", *req.RootId, err))
+				return err
+			}
+
+			if root.RootID != nil {
+				logger.Error(fmt.Sprintf("root message %s is itself a reply", *req.RootId))
+				return errInvalidReference
+			}
+
+			message.RootID = &root.ID
+		}
+
+		if req.ParentId != nil && *req.ParentId != "" {
+			parent, err := h.referencedMessage(ctx, streamId, *req.ParentId)
+			if err != nil {
+				logger.Error(fmt.Sprintf("invalid parent message %s: %v", *req.ParentId, err))
+				return err
+			}
+
+			switch {
+			case message.RootID != nil:
+				// ответ внутри треда может ссылаться только на корень или другой ответ этого же треда
+				if parent.ID != *message.RootID && (parent.RootID == nil || *parent.RootID != *message.RootID) {
+					logger.Error(fmt.Sprintf("parent message %s does not belong to thread %s", *req.ParentId, message.RootID))
+					return errInvalidReference
+				}
+			case parent.RootID != nil:
+				// ответ на сообщение из треда остаётся в этом треде
+				message.RootID = parent.RootID
+			}
+
+			message.ParentID = &parent.ID
+		}
+
+		err = h.repository.SaveMessage(ctx, &message)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to save message: %v", err))
+			return fmt.Errorf("failed to save message: %v", err)
+		}
+
+		sender, err := h.userSnapshot(ctx, senderID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get sender snapshot: %v", err))
+			return fmt.Errorf("failed to get sender snapshot: %v", err)
+		}
+
+		event := model.NewEventEnvelope(model.MessageCreatedEvent, model.MessageEventPayload{
+			Message: message,
+			Sender:  *sender,
+		})
+		err = h.repository.EnqueueOutbox(ctx, message.StreamID.String(), event)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to enqueue message publication: %v", err))
+			return fmt.Errorf("failed to enqueue message publication: %v", err)
+		}
+
+		// в каналах и комментариях подписчиков слишком много для рассылки по персональным каналам
+		if membership.StreamType != model.PrivateStreamType && membership.StreamType != model.GroupStreamType {
+			return nil
+		}
+
+		err = h.notifyStreamUpdated(ctx, streamId, nil, &message)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to enqueue stream updated events: %v", err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to send message transaction: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to send message: %v", err), errorStatus(err))
+		return
+	}
+
+	response := api.SendMessageResponse{
+		MessageId: message.ID.String(),
+		SentAt:    message.SentAt.Format(time.RFC3339),
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) GetMessageThread(w http.ResponseWriter, r *http.Request, streamId string, rootId string, params api.GetMessageThreadParams) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetMessageThread")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to find uuid")
+		h.writeError(w, "failed to find uuid", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := uuid.Parse(rootId); err != nil {
+		logger.Error(fmt.Sprintf("invalid root id: %v", err))
+		h.writeError(w, "invalid root id", http.StatusBadRequest)
+		return
+	}
+
+	isMember, err := h.repository.IsStreamMember(r.Context(), streamId, userUUID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to check stream membership: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if !isMember {
+		logger.Error("user is not a member of the stream")
+		h.writeError(w, "user is not a member of the stream", http.StatusForbidden)
+		return
+	}
+
+	root, err := h.repository.GetMessage(r.Context(), rootId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get root message: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to get root message: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if root == nil || root.StreamID.String() != streamId || root.DeletedAt != nil {
+		logger.Error("root message not found")
+		h.writeError(w, "root message not found", http.StatusNotFound)
+		return
+	}
+
+	var cursor *model.MessageCursor
+	switch {
+	case params.Cursor != nil && *params.Cursor != "":
+		parsed, err := model.ParseMessageCursor(*params.Cursor)
+		if err != nil {
+			logger.Error(fmt.Sprintf("invalid cursor: %v", err))
+			h.writeError(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		cursor = &parsed
+	case params.Offset != nil && *params.Offset != "":
+		parsed, err := model.CursorFromTimestamp(*params.Offset)
+		if err != nil {
+			logger.Error(fmt.Sprintf("invalid offset: %v", err))
+			h.writeError(w, "invalid offset", http.StatusBadRequest)
+			return
+		}
+		cursor = &parsed
+	}
+
+	limit := int32(defaultMessagesLimit)
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = int32(min(*params.Limit, maxMessagesLimit))
+	}
+
+	// запрашиваем на одно сообщение больше, чтобы понять, есть ли следующая страница
+	messages, err := h.repository.GetThreadMessages(r.Context(), streamId, rootId, userUUID, cursor, limit+1)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to fetch thread messages: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to fetch thread messages: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var nextCursor *string
+	if len(*messages) > int(limit) {
+		*messages = (*messages)[:limit]
+		encoded := model.NewMessageCursor((*messages)[limit-1]).Encode()
+		nextCursor = &encoded
+	}
+
+	readMarks, err := h.pageReadMarks(r.Context(), streamId, *messages)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to fetch read marks: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to fetch read marks: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	apiMessages := make([]api.Message, len(*messages))
+	for i, msg := range *messages {
+		apiMessages[i] = toAPIMessage(msg, readMarks)
+	}
+
+	response := api.GetMessageThreadResponse{
+		Messages:   apiMessages,
+		NextCursor: nextCursor,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) EditMessage(w http.ResponseWriter, r *http.Request, streamId string, messageId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("EditMessage")
+
+	var req api.EditMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := uuid.Parse(messageId); err != nil {
+		logger.Error(fmt.Sprintf("invalid message id: %v", err))
+		h.writeError(w, "invalid message id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.ValidateEditMessage(&req); err != nil {
+		logger.Error(fmt.Sprintf("message validation failed: %v", err))
+		h.writeError(w, fmt.Sprintf("message validation failed: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var message *model.Message
+	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if membership == nil {
+			return errNotStreamMember
+		}
+
+		if !membership.CanPost() {
+			return errPostingNotAllowed
+		}
+
+		message, err = h.repository.GetMessage(ctx, messageId)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get message: %v", err))
+			return fmt.Errorf("failed to get message: %v", err)
+		}
+
+		if message == nil || message.StreamID.String() != streamId || message.DeletedAt != nil {
+			return errMessageNotFound
+		}
+
+		if message.SenderID.String() != userUUID {
+			return errNotMessageSender
+		}
+
+		if time.Since(message.SentAt) > h.editWindow {
+			return errEditWindowExpired
+		}
+
+		updatedAt, err := h.repository.UpdateMessageContent(ctx, messageId, req.Content)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to update message: %v", err))
+			return fmt.Errorf("failed to update message: %v", err)
+		}
+
+		message.Content = req.Content
+		message.UpdatedAt = &updatedAt
+
+		sender, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get sender snapshot: %v", err))
+			return fmt.Errorf("failed to get sender snapshot: %v", err)
+		}
+
+		event := model.NewEventEnvelope(model.MessageEditedEvent, model.MessageEventPayload{
+			Message: *message,
+			Sender:  *sender,
+		})
+		err = h.repository.EnqueueOutbox(ctx, streamId, event)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to enqueue edited message publication: %v", err))
+			return fmt.Errorf("failed to enqueue edited message publication: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to edit message: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to edit message: %v", err), errorStatus(err))
+		return
+	}
+
+	response := api.EditMessageResponse{
+		MessageId: message.ID.String(),
+		UpdatedAt: message.UpdatedAt.Format(time.RFC3339),
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) DeleteMessage(w http.ResponseWriter, r *http.Request, streamId string, messageId string, params api.DeleteMessageParams) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("DeleteMessage")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := uuid.Parse(messageId); err != nil {
+		logger.Error(fmt.Sprintf("invalid message id: %v", err))
+		h.writeError(w, "invalid message id", http.StatusBadRequest)
+		return
+	}
+
+	format := string(params.Format)
+	if format != model.SelfDeleteFormat && format != model.AllDeleteFormat {
+		logger.Error(fmt.Sprintf("unsupported delete format: %s", format))
+		h.writeError(w, fmt.Sprintf("unsupported delete format: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if membership == nil {
+			return errNotStreamMember
+		}
+
+		message, err := h.repository.GetMessage(ctx, messageId)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get message: %v", err))
+			return fmt.Errorf("failed to get message: %v", err)
+		}
+
+		if message == nil || message.StreamID.String() != streamId || message.DeletedAt != nil {
+			return errMessageNotFound
+		}
+
+		if format == model.SelfDeleteFormat {
+			err = h.repository.HideMessage(ctx, messageId, userUUID)
+			if err != nil {
+				logger.Error(fmt.Sprintf("failed to hide message: %v", err))
+				return fmt.Errorf("failed to hide message: %v", err)
+			}
+			return nil
+		}
+
+		if message.SenderID.String() != userUUID && !membership.CanDeleteOthersMessages() {
+			return errNotMessageSender
+		}
+
+		deletedAt, err := h.repository.DeleteMessage(ctx, messageId, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to delete message: %v", err))
+			return fmt.Errorf("failed to delete message: %v", err)
+		}
+
+		actor, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get actor snapshot: %v", err))
+			return fmt.Errorf("failed to get actor snapshot: %v", err)
+		}
+
+		event := model.NewEventEnvelope(model.MessageDeletedEvent, model.MessageDeletedEventPayload{
+			Tombstone: model.MessageTombstone{
+				ID:        message.ID,
+				StreamID:  message.StreamID,
+				DeletedAt: deletedAt,
+				DeletedBy: uuid.MustParse(userUUID),
+			},
+			Actor: *actor,
+		})
+		err = h.repository.EnqueueOutbox(ctx, streamId, event)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to enqueue message tombstone publication: %v", err))
+			return fmt.Errorf("failed to enqueue message tombstone publication: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to delete message: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to delete message: %v", err), errorStatus(err))
+		return
+	}
+
+	response := api.DeleteMessageResponse{
+		MessageId: messageId,
+		Format:    format,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) SendTyping(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("SendTyping")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := uuid.Parse(streamId); err != nil {
+		logger.Error(fmt.Sprintf("invalid stream id: %v", err))
+		h.writeError(w, "invalid stream id", http.StatusBadRequest)
+		return
+	}
+
+	// лимит проверяется до запросов в базу, чтобы частые нажатия клавиш не нагружали Postgres
+	if allowed, retryAfter := h.typingLimiter.Allow(userUUID + ":" + streamId); !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		h.writeError(w, "typing events are rate limited", http.StatusTooManyRequests)
+		return
+	}
+
+	membership, err := h.repository.GetStreamMembership(r.Context(), streamId, userUUID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to check stream membership: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if membership == nil {
+		logger.Error(fmt.Sprintf("user %s is not a member of stream %s", userUUID, streamId))
+		h.writeError(w, errNotStreamMember.Error(), http.StatusForbidden)
+		return
+	}
+
+	if membership.StreamType != model.PrivateStreamType && membership.StreamType != model.GroupStreamType {
+		h.writeError(w, errWrongStreamType.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !membership.CanPost() {
+		h.writeError(w, errPostingNotAllowed.Error(), http.StatusForbidden)
+		return
+	}
+
+	expiresAt := time.Now().Add(h.typingTTL)
+	event := model.NewEventEnvelope(model.UserTypingEvent, model.UserTypingEventPayload{
+		StreamID:  uuid.MustParse(streamId),
+		UserID:    uuid.MustParse(userUUID),
+		ExpiresAt: expiresAt,
+	})
+
+	err = h.centrifugeClient.Publish(r.Context(), streamId, event)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to publish typing event: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to publish typing event: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := api.SendTypingResponse{
+		ExpiresAt: expiresAt.Unix(),
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) GetStreamPresence(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetStreamPresence")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	isMember, err := h.repository.IsStreamMember(r.Context(), streamId, userUUID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to check stream membership: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if !isMember {
+		logger.Error(fmt.Sprintf("user %s is not a member of stream %s", userUUID, streamId))
+		h.writeError(w, errNotStreamMember.Error(), http.StatusForbidden)
+		return
+	}
+
+	clients, err := h.presenceClient.Presence(r.Context(), streamId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get stream presence: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to get stream presence: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	seen := make(map[string]struct{}, len(clients))
+	userIDs := make([]string, 0, len(clients))
+	for _, client := range clients {
+		if _, ok := seen[client.User]; ok {
+			continue
+		}
+		seen[client.User] = struct{}{}
+		userIDs = append(userIDs, client.User)
+	}
+	sort.Strings(userIDs)
+
+	response := api.GetStreamPresenceResponse{
+		NumClients: len(clients),
+		NumUsers:   len(userIDs),
+		UserIds:    userIDs,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) MarkStreamRead(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("MarkStreamRead")
+
+	var req api.MarkStreamReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := uuid.Parse(req.MessageId); err != nil {
+		logger.Error(fmt.Sprintf("invalid message id: %v", err))
+		h.writeError(w, "invalid message id", http.StatusBadRequest)
+		return
+	}
+
+	var updated bool
+	err := tx.TxExecute(r.Context(), func(ctx context.Context) error {
+		membership, err := h.repository.GetStreamMembership(ctx, streamId, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			return fmt.Errorf("failed to check stream membership: %v", err)
+		}
+
+		if membership == nil {
+			return errNotStreamMember
+		}
+
+		message, err := h.repository.GetMessage(ctx, req.MessageId)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get message: %v", err))
+			return fmt.Errorf("failed to get message: %v", err)
+		}
+
+		if message == nil || message.StreamID.String() != streamId {
+			return errMessageNotFound
+		}
+
+		readAt, err := h.repository.MarkStreamRead(ctx, streamId, userUUID, req.MessageId)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to mark stream read: %v", err))
+			return fmt.Errorf("failed to mark stream read: %v", err)
+		}
+
+		updated = readAt != nil
+		if !updated {
+			return nil
+		}
+
+		actor, err := h.userSnapshot(ctx, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get actor snapshot: %v", err))
+			return fmt.Errorf("failed to get actor snapshot: %v", err)
+		}
+
+		event := model.NewEventEnvelope(model.MessagesReadEvent, model.MessagesReadEventPayload{
+			ReadMark: model.ReadMark{
+				StreamID:  uuid.MustParse(streamId),
+				UserID:    uuid.MustParse(userUUID),
+				MessageID: uuid.MustParse(req.MessageId),
+				ReadAt:    *readAt,
+			},
+			Actor: *actor,
+		})
+		err = h.repository.EnqueueOutbox(ctx, streamId, event)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to enqueue read event publication: %v", err))
+			return fmt.Errorf("failed to enqueue read event publication: %v", err)
+		}
+
+		err = h.notifyStreamUpdated(ctx, streamId, []string{userUUID}, nil)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to enqueue stream updated event: %v", err))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to mark stream read: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to mark stream read: %v", err), errorStatus(err))
+		return
+	}
+
+	response := api.MarkStreamReadResponse{
+		Updated: updated,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("UploadAttachment")
+
+	uploaderID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get uploader ID")
+		h.writeError(w, "failed to get uploader ID", http.StatusInternalServerError)
+		return
+	}
+
+	uploaderID, err := canonicalUUID(uploaderID)
+	if err != nil {
+		h.writeError(w, "invalid uploader id", http.StatusBadRequest)
+		return
+	}
+
+	// запас на заголовки multipart
+	r.Body = http.MaxBytesReader(w, r.Body, h.storage.MaxUploadSize+multipartOverhead)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.writeError(w, errUploadTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		logger.Error(fmt.Sprintf("failed to read uploaded file: %v", err))
+		h.writeError(w, "invalid multipart body", http.StatusBadRequest)
+		return
+	}
+	defer file.Close() //nolint:errcheck // .
+
+	data, err := io.ReadAll(io.LimitReader(file, h.storage.MaxUploadSize+1))
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to read uploaded file: %v", err))
+		h.writeError(w, "failed to read uploaded file", http.StatusBadRequest)
+		return
+	}
+
+	mimeType := attachmentMimeType(header.Header.Get("Content-Type"), data)
+	size := int64(len(data))
+	if err := h.checkUpload(mimeType, size); err != nil {
+		logger.Error(fmt.Sprintf("upload rejected: %v", err))
+		h.writeError(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	attachment := model.Attachment{
+		StorageKey: attachmentKey(uploaderID),
+		UploaderID: uuid.MustParse(uploaderID),
+		MimeType:   mimeType,
+		Size:       size,
+	}
+
+	err = h.blobStore.Put(r.Context(), attachment.StorageKey, bytes.NewReader(data), size, mimeType)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to store attachment: %v", err))
+		h.writeError(w, "failed to store attachment", http.StatusInternalServerError)
+		return
+	}
+
+	// превью сохраняется после оригинала, чтобы не оставить превью без оригинала
+	err = h.storeThumbnail(r.Context(), &attachment, data)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to store thumbnail: %v", err))
+		h.deleteObject(r.Context(), attachment.StorageKey)
+		h.writeError(w, "failed to store attachment", http.StatusInternalServerError)
+		return
+	}
+
+	err = h.repository.SaveAttachment(r.Context(), &attachment)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to save attachment: %v", err))
+		if attachment.ThumbnailKey != nil {
+			h.deleteObject(r.Context(), *attachment.ThumbnailKey)
+		}
+		h.deleteObject(r.Context(), attachment.StorageKey)
+		h.writeError(w, "failed to save attachment", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, toAPIAttachment(attachment), http.StatusOK)
+}
+
+func (h *Handler) PresignAttachment(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("PresignAttachment")
+
+	var req api.PresignAttachmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	uploaderID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get uploader ID")
+		h.writeError(w, "failed to get uploader ID", http.StatusInternalServerError)
+		return
+	}
+
+	uploaderID, err := canonicalUUID(uploaderID)
+	if err != nil {
+		h.writeError(w, "invalid uploader id", http.StatusBadRequest)
+		return
+	}
+
+	mimeType, _, err := mime.ParseMediaType(req.MimeType)
+	if err != nil {
+		logger.Error(fmt.Sprintf("invalid mime type: %v", err))
+		h.writeError(w, "invalid mime type", http.StatusBadRequest)
+		return
+	}
+
+	if req.Size <= 0 {
+		h.writeError(w, "size must be positive", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.checkUpload(mimeType, req.Size); err != nil {
+		logger.Error(fmt.Sprintf("upload rejected: %v", err))
+		h.writeError(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	// запись о вложении появится только после подтверждения загрузки в CompleteAttachment
+	storageKey := attachmentKey(uploaderID)
+	expiresAt := time.Now().Add(h.storage.PresignTTL)
+	uploadURL, err := h.blobStore.PresignPut(r.Context(), storageKey, mimeType, req.Size, h.storage.PresignTTL)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to presign upload: %v", err))
+		if errors.Is(err, blobstore.ErrPresignNotSupported) {
+			h.writeError(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		h.writeError(w, "failed to presign upload", http.StatusInternalServerError)
+		return
+	}
+
+	response := api.PresignAttachmentResponse{
+		StorageKey: storageKey,
+		UploadUrl:  uploadURL,
+		Method:     http.MethodPut,
+		Headers: map[string]string{
+			"Content-Type": mimeType,
+		},
+		ExpiresAt: expiresAt.Unix(),
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) CompleteAttachment(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("CompleteAttachment")
+
+	var req api.CompleteAttachmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	uploaderID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get uploader ID")
+		h.writeError(w, "failed to get uploader ID", http.StatusInternalServerError)
+		return
+	}
+
+	uploaderID, err := canonicalUUID(uploaderID)
+	if err != nil {
+		h.writeError(w, "invalid uploader id", http.StatusBadRequest)
+		return
+	}
+
+	if !isAttachmentKeyOf(req.StorageKey, uploaderID) {
+		h.writeError(w, "invalid storage key", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.repository.GetAttachment(r.Context(), req.StorageKey)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get attachment: %v", err))
+		h.writeError(w, "failed to get attachment", http.StatusInternalServerError)
+		return
+	}
+
+	// повторное подтверждение возвращает уже сохранённое вложение
+	if existing != nil {
+		h.writeJSON(w, toAPIAttachment(*existing), http.StatusOK)
+		return
+	}
+
+	data, err := h.readObject(r.Context(), req.StorageKey)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to read uploaded object: %v", err))
+		h.writeError(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	// заявленному при presign типу не доверяем: определяем его по содержимому
+	mimeType := attachmentMimeType("", data)
+	size := int64(len(data))
+	if err := h.checkUpload(mimeType, size); err != nil {
+		logger.Error(fmt.Sprintf("upload rejected: %v", err))
+		h.deleteObject(r.Context(), req.StorageKey)
+		h.writeError(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	attachment := model.Attachment{
+		StorageKey: req.StorageKey,
+		UploaderID: uuid.MustParse(uploaderID),
+		MimeType:   mimeType,
+		Size:       size,
+	}
+
+	err = h.storeThumbnail(r.Context(), &attachment, data)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to store thumbnail: %v", err))
+		h.writeError(w, "failed to store attachment", http.StatusInternalServerError)
+		return
+	}
+
+	err = h.repository.SaveAttachment(r.Context(), &attachment)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to save attachment: %v", err))
+		if attachment.ThumbnailKey != nil {
+			h.deleteObject(r.Context(), *attachment.ThumbnailKey)
+		}
+		h.writeError(w, "failed to save attachment", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, toAPIAttachment(attachment), http.StatusOK)
+}
+
+func (h *Handler) GetConnectAccessToken(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetConnectAccessToken")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	personalChannel := model.PersonalChannel(userUUID)
+
+	token, expiresAt, err := h.jwtGenerator.GenerateConnectToken(userUUID, []string{personalChannel})
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to generate access token: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to generate access token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info(fmt.Sprintf("generated access token for user %s", userUUID))
+
+	response := api.GetConnectAccessTokenResponse{
+		Token:           token,
+		ExpiresAt:       expiresAt,
+		PersonalChannel: personalChannel,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) GetStreamSubscribeToken(w http.ResponseWriter, r *http.Request, streamId string) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetStreamSubscribeToken")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	isMember, err := h.repository.IsStreamMember(r.Context(), streamId, userUUID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to check stream membership: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if !isMember {
+		logger.Error("user is not a member of the stream")
+		h.writeError(w, "user is not a member of the stream", http.StatusForbidden)
+		return
+	}
+
+	token, expiresAt, err := h.jwtGenerator.GenerateSubscribeToken(userUUID, streamId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to generate subscribe token: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to generate subscribe token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info(fmt.Sprintf("generated subscribe token for user %s, stream %s", userUUID, streamId))
+
+	response := api.GetStreamSubscribeTokenResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		Channel:   streamId,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) GetUserActiveStreams(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetUserActiveStreams")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	streamIDs, err := h.repository.GetUserActiveStreams(r.Context(), userUUID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get user active streams: %v", err))
+		h.writeError(w, fmt.Sprintf("failed to get user active streams: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := api.GetUserActiveStreamsResponse{
+		StreamIds: streamIDs,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) GetBatchSubscribeTokens(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("GetBatchSubscribeTokens")
+
+	var req api.GetBatchSubscribeTokensRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	var subscriptions []api.StreamSubscription
+
+	for _, streamID := range req.StreamIds {
+		isMember, err := h.repository.IsStreamMember(r.Context(), streamID, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership for %s: %v", streamID, err))
+			continue
+		}
+
+		if !isMember {
+			logger.Warn(fmt.Sprintf("user %s is not a member of stream %s, skipping", userUUID, streamID))
+			continue
+		}
+
+		token, expiresAt, err := h.jwtGenerator.GenerateSubscribeToken(userUUID, streamID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to generate subscribe token for stream %s: %v", streamID, err))
+			continue
+		}
+
+		subscriptions = append(subscriptions, api.StreamSubscription{
+			StreamId:  streamID,
+			Token:     token,
+			ExpiresAt: expiresAt,
+			Channel:   streamID,
+		})
+	}
+
+	response := api.GetBatchSubscribeTokensResponse{
+		Subscriptions: subscriptions,
+	}
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request, params api.StreamEventsParams) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("StreamEvents")
+
+	userUUID, ok := r.Context().Value(config.KeyUUID).(string)
+	if !ok {
+		logger.Error("failed to get user UUID")
+		h.writeError(w, "failed to get user UUID", http.StatusInternalServerError)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		logger.Error("response writer does not support flushing")
+		h.writeError(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var streamIDs []string
+	if params.StreamId != nil {
+		streamIDs = *params.StreamId
+	}
+
+	for _, streamID := range streamIDs {
+		if _, err := uuid.Parse(streamID); err != nil {
+			logger.Error(fmt.Sprintf("invalid stream id %s: %v", streamID, err))
+			h.writeError(w, fmt.Sprintf("invalid stream id: %s", streamID), http.StatusBadRequest)
+			return
+		}
+
+		isMember, err := h.repository.IsStreamMember(r.Context(), streamID, userUUID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to check stream membership: %v", err))
+			h.writeError(w, fmt.Sprintf("failed to check stream membership: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if !isMember {
+			logger.Error(fmt.Sprintf("user %s is not a member of stream %s", userUUID, streamID))
+			h.writeError(w, errNotStreamMember.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	lastEventID := ""
+	if params.LastEventID != nil {
+		lastEventID = *params.LastEventID
+	} else if params.LastEventId != nil {
+		lastEventID = *params.LastEventId
+	}
+
+	var after model.EventCursor
+	if lastEventID != "" {
+		var err error
+		after, err = model.ParseEventCursor(lastEventID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("invalid last event id %s: %v", lastEventID, err))
+			h.writeError(w, "invalid last event id", http.StatusBadRequest)
+			return
+		}
+	} else {
+		var err error
+		after, err = h.repository.GetLastEventCursor(r.Context())
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to get last event cursor: %v", err))
+			h.writeError(w, fmt.Sprintf("failed to get last event cursor: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(h.sse.PollInterval)
+	defer ticker.Stop()
+
+	lastWrite := time.Now()
+
+	for {
+		// членство проверяется в самом запросе, поэтому исключённый участник перестаёт получать события сразу
+		events, err := h.repository.GetEventsAfter(r.Context(), userUUID, streamIDs, after, h.sse.BatchSize)
+		if err != nil {
+			if r.Context().Err() == nil {
+				logger.Error(fmt.Sprintf("failed to get events: %v", err))
+			}
+			return
+		}
+
+		for _, event := range events {
+			after = event.Cursor()
+			if err := writeSSEEvent(w, after.Encode(), event.Type, event.Payload); err != nil {
+				logger.Warn(fmt.Sprintf("failed to write event: %v", err))
+				return
+			}
+		}
+
+		if len(events) > 0 {
+			flusher.Flush()
+			lastWrite = time.Now()
+		}
+
+		if len(events) == h.sse.BatchSize && r.Context().Err() == nil {
+			continue
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		if time.Since(lastWrite) >= h.sse.HeartbeatInterval {
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+			lastWrite = time.Now()
+		}
+	}
+}
+
+func (h *Handler) CentrifugoConnect(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("CentrifugoConnect")
+
+	var req api.CentrifugoConnectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// заголовок X-User-ID Centrifugo пробрасывает из исходного запроса клиента. Доверять ему можно,
+	// только если /centrifugo/* доступны исключительно Centrifugo: proxy-ключ — единственная защита
+	userUUID := strings.TrimSpace(r.Header.Get("X-User-ID"))
+	if _, err := uuid.Parse(userUUID); err != nil {
+		logger.Warn(fmt.Sprintf("rejected connect of client %s: missing or invalid X-User-ID", req.Client))
+		h.writeJSON(w, api.CentrifugoConnectResponse{
+			Disconnect: &api.CentrifugoDisconnect{
+				Code:   proxyUnauthorizedDisconnect,
+				Reason: "unauthorized",
+			},
+		}, http.StatusOK)
+		return
+	}
+
+	h.touchLastOnline(r.Context(), userUUID)
+
+	channels := []string{model.PersonalChannel(userUUID)}
+	result := &api.CentrifugoConnectResult{
+		User:     userUUID,
+		Channels: &channels,
+	}
+	if h.proxyTTL > 0 {
+		expireAt := time.Now().Add(h.proxyTTL).Unix()
+		result.ExpireAt = &expireAt
+	}
+
+	h.writeJSON(w, api.CentrifugoConnectResponse{Result: result}, http.StatusOK)
+}
+
+func (h *Handler) CentrifugoSubscribe(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("CentrifugoSubscribe")
+
+	var req api.CentrifugoSubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	allowed, err := h.canSubscribe(r.Context(), req.User, req.Channel)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to check subscription to %s: %v", req.Channel, err))
+		h.writeError(w, fmt.Sprintf("failed to check subscription: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if !allowed {
+		logger.Warn(fmt.Sprintf("user %s is not allowed to subscribe to %s", req.User, req.Channel))
+		h.writeJSON(w, proxyPermissionDenied(), http.StatusOK)
+		return
+	}
+
+	h.writeJSON(w, api.CentrifugoProxyResponse{Result: &api.CentrifugoProxyResult{}}, http.StatusOK)
+}
+
+func (h *Handler) CentrifugoRefresh(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("CentrifugoRefresh")
+
+	var req api.CentrifugoRefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := uuid.Parse(req.User); err != nil {
+		expired := true
+		h.writeJSON(w, api.CentrifugoRefreshResponse{
+			Result: &api.CentrifugoRefreshResult{Expired: &expired},
+		}, http.StatusOK)
+		return
+	}
+
+	h.touchLastOnline(r.Context(), req.User)
+
+	expireAt := time.Now().Add(h.proxyTTL).Unix()
+	h.writeJSON(w, api.CentrifugoRefreshResponse{
+		Result: &api.CentrifugoRefreshResult{ExpireAt: &expireAt},
+	}, http.StatusOK)
+}
+
+func (h *Handler) CentrifugoPublish(w http.ResponseWriter, r *http.Request) {
+	logger := logger_lib.FromContext(r.Context(), config.KeyLogger)
+	logger.AddFuncName("CentrifugoPublish")
+
+	var req api.CentrifugoPublishRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error(fmt.Sprintf("failed to decode request: %v", err))
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// в каналы стримов и персональные каналы публикует только сервис: сообщения и typing клиенты отправляют
+	// через REST, где они сохраняются, проверяются права и собирается конверт события
+	logger.Warn(fmt.Sprintf("rejected client publication of user %s to %s", req.User, req.Channel))
+	h.writeJSON(w, proxyPermissionDenied(), http.StatusOK)
+}
+
+// ----------------------------- helpers -----------------------------
+
+func (h *Handler) canSubscribe(ctx context.Context, userID, channel string) (bool, error) {
+	if strings.HasPrefix(channel, model.PersonalChannelPrefix) {
+		return channel == model.PersonalChannel(userID), nil
+	}
+
+	if _, err := uuid.Parse(channel); err != nil {
+		return false, nil
+	}
+
+	return h.repository.IsStreamMember(ctx, channel, userID)
+}
+
+// touchLastOnline вызывается из connect и refresh proxy. Centrifugo не сообщает об отключении,
+// поэтому между refresh last_online подключённых пользователей продлевает presence.Sweeper
+func (h *Handler) touchLastOnline(ctx context.Context, userID string) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+
+	if err := h.repository.UpdateUserLastOnline(ctx, userID); err != nil {
+		logger.Warn(fmt.Sprintf("failed to update last online of user %s: %v", userID, err))
+	}
+}
+
+// onlineUsers проверяет presence персональных каналов собеседников одним запросом к Centrifugo;
+// ошибка Centrifugo не ломает список стримов, собеседник без ответа считается офлайн
+func (h *Handler) onlineUsers(ctx context.Context, streams model.PrivateStreamPreviewList) []bool {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+
+	online := make([]bool, len(streams))
+	if len(streams) == 0 {
+		return online
+	}
+
+	channels := make([]string, len(streams))
+	for i, stream := range streams {
+		channels[i] = model.PersonalChannel(stream.CompanionID)
+	}
+
+	stats, err := h.presenceClient.PresenceStatsBatch(ctx, channels)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("failed to get presence of companions: %v", err))
+	}
+
+	for i := range stats {
+		online[i] = stats[i] != nil && stats[i].NumClients > 0
+	}
+
+	return online
+}
+
+func toAPIStreamNotifications(streamID string, settings model.NotificationSettings, now time.Time) api.StreamNotificationsResponse {
+	response := api.StreamNotificationsResponse{
+		StreamId: streamID,
+		Level:    api.NotifyLevel(settings.Level),
+		Muted:    settings.Muted(now),
+	}
+
+	if response.Muted && settings.MutedUntil != nil {
+		mutedUntil := settings.MutedUntil.Format(time.RFC3339)
+		response.MutedUntil = &mutedUntil
+	}
+
+	return response
+}
+
+func toAPIStreamPreview(stream model.StreamPreview) api.StreamPreview {
+	preview := api.StreamPreview{
+		StreamId:       stream.StreamID,
+		Type:           api.StreamPreviewType(stream.StreamType),
+		StreamName:     stream.StreamName,
+		Role:           stream.Role,
+		CompanionId:    stream.CompanionID,
+		LastActivityAt: stream.ActivityAt.Format(time.RFC3339),
+		UnreadCount:    stream.UnreadCount,
+		Muted:          stream.Muted,
+		NotifyLevel:    api.NotifyLevel(stream.NotifyLevel),
+	}
+
+	if stream.AvatarURL != "" {
+		preview.AvatarUrl = &stream.AvatarURL
+	}
+
+	if stream.Muted && stream.MutedUntil != nil {
+		mutedUntil := stream.MutedUntil.Format(time.RFC3339)
+		preview.MutedUntil = &mutedUntil
+	}
+
+	if stream.LastMessageID != nil && stream.LastMessageSenderID != nil && stream.LastMessageTimestamp != nil {
+		preview.LastMessage = &api.StreamLastMessage{
+			Id:             *stream.LastMessageID,
+			Content:        stream.LastMessageContent,
+			SenderId:       *stream.LastMessageSenderID,
+			SenderNickname: stream.LastMessageSenderNickname,
+			SentAt:         stream.LastMessageTimestamp.Format(time.RFC3339),
+		}
+	}
+
+	return preview
+}
+
+func writeSSEEvent(w io.Writer, id, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, event, payload)
+	return err
+}
+
+func proxyPermissionDenied() api.CentrifugoProxyResponse {
+	return api.CentrifugoProxyResponse{
+		Error: &api.CentrifugoProxyError{
+			Code:    proxyPermissionDeniedCode,
+			Message: "permission denied",
+		},
+	}
+}
+
+func (h *Handler) streamMetadata(req *api.CreateStreamRequest) (string, error) {
+	if req.Type != model.GroupStreamType && req.Type != model.ChannelStreamType {
+		return req.ChatMetadata, nil
+	}
+
+	metadata := model.StreamMetadata{
+		Title: strings.TrimSpace(*req.Title),
+	}
+	if req.AvatarUrl != nil {
+		metadata.AvatarURL = *req.AvatarUrl
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(api.Error{Error: message})
+}
+
+// userSnapshot возвращает профиль пользователя для события; если профиль ещё не синхронизирован, отдаётся только ID
+func (h *Handler) userSnapshot(ctx context.Context, userID string) (*model.UserSnapshot, error) {
+	snapshot, err := h.repository.GetUserSnapshot(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot == nil {
+		return &model.UserSnapshot{ID: uuid.MustParse(userID)}, nil
+	}
+
+	return snapshot, nil
+}
+
+func (h *Handler) notifyStreamAdded(ctx context.Context, streamID, streamType string, member model.StreamMember, actor model.UserSnapshot) error {
+	event := model.NewEventEnvelope(model.StreamAddedEvent, model.StreamAddedEventPayload{
+		StreamID:   uuid.MustParse(streamID),
+		StreamType: streamType,
+		Role:       member.Role,
+		Actor:      actor,
+	})
+
+	err := h.repository.EnqueueOutbox(ctx, model.PersonalChannel(member.UserID), event)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue stream added event: %v", err)
+	}
+
+	return nil
+}
+
+// notifyStreamUpdated рассылает актуальный счётчик непрочитанных в персональные каналы участников;
+// пустой userIDs означает всех участников, lastMessage поднимает стрим в списке
+func (h *Handler) notifyStreamUpdated(ctx context.Context, streamID string, userIDs []string, lastMessage *model.Message) error {
+	members, err := h.repository.GetStreamPersonalChannels(ctx, streamID, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get stream personal channels: %v", err)
+	}
+
+	for _, member := range members {
+		payload := model.StreamUpdatedEventPayload{
+			StreamID:    uuid.MustParse(streamID),
+			UnreadCount: member.UnreadCount,
+		}
+		if lastMessage != nil {
+			payload.LastMessageID = &lastMessage.ID
+			payload.LastMessageAt = &lastMessage.SentAt
+		}
+
+		err = h.repository.EnqueueOutbox(ctx, member.Channel, model.NewEventEnvelope(model.StreamUpdatedEvent, payload))
+		if err != nil {
+			return fmt.Errorf("failed to enqueue stream updated event: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// leaveStream завершает участие, отписывает от канала стрима и уведомляет оставшихся участников и самого пользователя
+func (h *Handler) leaveStream(ctx context.Context, streamID, userID string, actor model.UserSnapshot) error {
+	left, err := h.repository.LeaveStream(ctx, streamID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !left {
+		return errMemberNotFound
+	}
+
+	err = h.repository.RemoveUserSubscription(ctx, userID, streamID)
+	if err != nil {
+		return err
+	}
+
+	err = h.notifyMembership(ctx, model.MemberLeftEvent, streamID, userID, actor)
+	if err != nil {
+		return err
+	}
+
+	event := model.NewEventEnvelope(model.StreamRemovedEvent, model.StreamRemovedEventPayload{
+		StreamID: uuid.MustParse(streamID),
+		Actor:    actor,
+	})
+
+	err = h.repository.EnqueueOutbox(ctx, model.PersonalChannel(userID), event)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue stream removed event: %v", err)
+	}
+
+	return nil
+}
+
+// unsubscribeFromStream закрывает живые подписки бывшего участника на канал стрима; вызывается после коммита,
+// иначе при откате транзакции пользователь остался бы без подписки. Если Centrifugo недоступен,
+// подписка доживёт до переподключения или обновления токена, где членство проверяется заново
+func (h *Handler) unsubscribeFromStream(ctx context.Context, userID, streamID string) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+
+	if err := h.presenceClient.Unsubscribe(ctx, userID, streamID); err != nil {
+		logger.Warn(fmt.Sprintf("failed to unsubscribe user %s from stream %s: %v", userID, streamID, err))
+	}
+}
+
+func (h *Handler) changeMemberRole(ctx context.Context, streamID, userID, role string, actor model.UserSnapshot) error {
+	err := h.repository.UpdateMemberRole(ctx, streamID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	member := actor
+	if userID != actor.ID.String() {
+		snapshot, err := h.userSnapshot(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get member snapshot: %v", err)
+		}
+		member = *snapshot
+	}
+
+	event := model.NewEventEnvelope(model.MemberRoleChangedEvent, model.MemberRoleChangedEventPayload{
+		StreamID: uuid.MustParse(streamID),
+		Member:   member,
+		Role:     role,
+		Actor:    actor,
+	})
+
+	err = h.repository.EnqueueOutbox(ctx, streamID, event)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue member role changed event: %v", err)
+	}
+
+	return nil
+}
+
+// notifyMembership публикует системное событие о составе в канал стрима
+func (h *Handler) notifyMembership(ctx context.Context, eventType, streamID, memberID string, actor model.UserSnapshot) error {
+	member := actor
+	if memberID != actor.ID.String() {
+		snapshot, err := h.userSnapshot(ctx, memberID)
+		if err != nil {
+			return fmt.Errorf("failed to get member snapshot: %v", err)
+		}
+		member = *snapshot
+	}
+
+	event := model.NewEventEnvelope(eventType, model.MemberEventPayload{
+		StreamID: uuid.MustParse(streamID),
+		Member:   member,
+		Actor:    actor,
+	})
+
+	err := h.repository.EnqueueOutbox(ctx, streamID, event)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue %s event: %v", eventType, err)
+	}
+
+	return nil
+}
+
+func (h *Handler) referencedMessage(ctx context.Context, streamID, messageID string) (*model.Message, error) {
+	message, err := h.repository.GetMessage(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %v", err)
+	}
+
+	if message == nil || message.StreamID.String() != streamID || message.DeletedAt != nil {
+		return nil, errInvalidReference
+	}
+
+	return message, nil
+}
+
+func toAPIMessage(msg model.Message, readMarks model.ReadMarkList) api.Message {
+	var updatedAt *string
+	if msg.UpdatedAt != nil {
+		timestamp := msg.UpdatedAt.Format(time.RFC3339)
+		updatedAt = &timestamp
+	}
+
+	var rootUuid *string
+	if msg.RootID != nil {
+		uuid := msg.RootID.String()
+		rootUuid = &uuid
+	}
+
+	var parentUuid *string
+	if msg.ParentID != nil {
+		uuid := msg.ParentID.String()
+		parentUuid = &uuid
+	}
+
+	return api.Message{
+		Uuid:        msg.ID.String(),
+		Content:     msg.Content,
+		MessageType: msg.Type,
+		Media:       toAPIMedia(msg.Media),
+		SentAt:      msg.SentAt.Format(time.RFC3339),
+		UpdatedAt:   updatedAt,
+		RootUuid:    rootUuid,
+		ParentUuid:  parentUuid,
+		ReadBy:      readBy(msg, readMarks),
+		ReplyCount:  msg.ReplyCount,
+	}
+}
+
+func toModelMedia(media *api.MessageMedia) *model.MessageMedia {
+	if media == nil {
+		return nil
+	}
+
+	result := &model.MessageMedia{
+		StorageKey: media.StorageKey,
+		MimeType:   media.MimeType,
+		Size:       media.Size,
+	}
+	if media.Width != nil {
+		result.Width = *media.Width
+	}
+	if media.Height != nil {
+		result.Height = *media.Height
+	}
+	if media.DurationMs != nil {
+		result.DurationMs = *media.DurationMs
+	}
+	if media.Waveform != nil {
+		result.Waveform = *media.Waveform
+	}
+
+	return result
+}
+
+func toAPIMedia(media *model.MessageMedia) *api.MessageMedia {
+	if media == nil {
+		return nil
+	}
+
+	result := &api.MessageMedia{
+		StorageKey: media.StorageKey,
+		MimeType:   media.MimeType,
+		Size:       media.Size,
+	}
+	if media.Width > 0 {
+		result.Width = &media.Width
+	}
+	if media.Height > 0 {
+		result.Height = &media.Height
+	}
+	if media.DurationMs > 0 {
+		result.DurationMs = &media.DurationMs
+	}
+	if len(media.Waveform) > 0 {
+		result.Waveform = &media.Waveform
+	}
+	if media.ThumbnailKey != "" {
+		result.ThumbnailKey = &media.ThumbnailKey
+	}
+
+	return result
+}
+
+func readBy(msg model.Message, marks model.ReadMarkList) []string {
+	readers := make([]string, 0)
+	position := model.NewMessageCursor(msg)
+	for _, mark := range marks {
+		if mark.UserID == msg.SenderID {
+			continue
+		}
+		if !(model.MessageCursor{SentAt: mark.SentAt, ID: mark.MessageID}).Before(position) {
+			readers = append(readers, mark.UserID.String())
+		}
+	}
+
+	return readers
+}
+
+// pageReadMarks загружает только отметки не раньше самого старого сообщения страницы:
+// более ранние не попадают ни в один read_by
+func (h *Handler) pageReadMarks(ctx context.Context, streamID string, pages ...model.MessageList) (model.ReadMarkList, error) {
+	var oldest *model.MessageCursor
+	for _, messages := range pages {
+		for _, msg := range messages {
+			position := model.NewMessageCursor(msg)
+			if oldest == nil || position.Before(*oldest) {
+				oldest = &position
+			}
+		}
+	}
+
+	if oldest == nil {
+		return nil, nil
+	}
+
+	return h.repository.GetStreamReadMarks(ctx, streamID, *oldest)
+}
+
+func (h *Handler) checkUpload(mimeType string, size int64) error {
+	if size > h.storage.MaxUploadSize {
+		return errUploadTooLarge
+	}
+
+	for _, allowed := range h.storage.AllowedMimeTypes {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == mimeType {
+			return nil
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mimeType, prefix+"/") {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", errMimeNotAllowed, mimeType)
+}
+
+func attachmentKey(uploaderID string) string {
+	return fmt.Sprintf("attachments/%s/%s", uploaderID, uuid.New())
+}
+
+func canonicalUUID(id string) (string, error) {
+	parsed, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return "", err
+	}
+
+	return parsed.String(), nil
+}
+
+func isAttachmentKeyOf(storageKey, uploaderID string) bool {
+	id, ok := strings.CutPrefix(storageKey, fmt.Sprintf("attachments/%s/", uploaderID))
+	if !ok {
+		return false
+	}
+
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+// readObject читает загруженный объект целиком, но не больше лимита загрузки
+func (h *Handler) readObject(ctx context.Context, key string) ([]byte, error) {
+	body, err := h.blobStore.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrObjectNotFound) {
+			return nil, errNotUploaded
+		}
+		return nil, fmt.Errorf("failed to get object: %v", err)
+	}
+	defer body.Close() //nolint:errcheck // .
+
+	data, err := io.ReadAll(io.LimitReader(body, h.storage.MaxUploadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %v", err)
+	}
+
+	return data, nil
+}
+
+func (h *Handler) deleteObject(ctx context.Context, key string) {
+	if err := h.blobStore.Delete(ctx, key); err != nil {
+		logger := logger_lib.FromContext(ctx, config.KeyLogger)
+		logger.Warn(fmt.Sprintf("failed to delete object %s: %v", key, err))
+	}
+}
+
+// storeThumbnail сохраняет превью изображения и заполняет размеры вложения;
+// неподдерживаемый формат не мешает загрузке оригинала
+func (h *Handler) storeThumbnail(ctx context.Context, attachment *model.Attachment, data []byte) error {
+	if !strings.HasPrefix(attachment.MimeType, "image/") {
+		return nil
+	}
+
+	thumb, err := thumbnail.Generate(data, h.storage.ThumbnailSize)
+	if err != nil {
+		logger := logger_lib.FromContext(ctx, config.KeyLogger)
+		logger.Error(fmt.Sprintf("failed to generate thumbnail: %v", err))
+		return nil
+	}
+
+	thumbnailKey := attachment.StorageKey + thumbnailSuffix
+	err = h.blobStore.Put(ctx, thumbnailKey, bytes.NewReader(thumb.Data), int64(len(thumb.Data)), thumbnail.MimeType)
+	if err != nil {
+		return fmt.Errorf("failed to put thumbnail: %v", err)
+	}
+
+	attachment.Width = &thumb.Width
+	attachment.Height = &thumb.Height
+	attachment.ThumbnailKey = &thumbnailKey
+
+	return nil
+}
+
+func toAPIAttachment(attachment model.Attachment) api.Attachment {
+	return api.Attachment{
+		StorageKey:   attachment.StorageKey,
+		MimeType:     attachment.MimeType,
+		Size:         attachment.Size,
+		Width:        attachment.Width,
+		Height:       attachment.Height,
+		ThumbnailKey: attachment.ThumbnailKey,
+	}
+}
+
+// если клиент не передал тип или передал общий, определяем его по содержимому
+func attachmentMimeType(declared string, data []byte) string {
+	mimeType, _, err := mime.ParseMediaType(declared)
+	if err != nil || mimeType == "application/octet-stream" {
+		mimeType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+
+	return mimeType
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errNotStreamMember), errors.Is(err, errPostingNotAllowed),
+		errors.Is(err, errNotMessageSender), errors.Is(err, errEditWindowExpired),
+		errors.Is(err, errNotStreamAdmin), errors.Is(err, errNotStreamOwner):
+		return http.StatusForbidden
+	case errors.Is(err, errStreamNotFound), errors.Is(err, errMessageNotFound),
+		errors.Is(err, errMemberNotFound), errors.Is(err, errNotUploaded):
+		return http.StatusNotFound
+	case errors.Is(err, errOwnerCannotLeave):
+		return http.StatusConflict
+	case errors.Is(err, errWrongStreamType), errors.Is(err, errInvalidReference),
+		errors.Is(err, errInvalidAttachment):
+		return http.StatusBadRequest
+	case errors.Is(err, errUploadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, errMimeNotAllowed):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError
+	}
 }
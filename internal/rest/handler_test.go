@@ -4,9 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"strings"
 	"testing"
 	"time"
@@ -19,6 +26,7 @@ import (
 
 	logger_lib "github.com/s21platform/logger-lib"
 
+	"github.com/s21platform/chat-service/internal/client/blobstore"
 	"github.com/s21platform/chat-service/internal/config"
 	api "github.com/s21platform/chat-service/internal/generated"
 	"github.com/s21platform/chat-service/internal/model"
@@ -34,6 +42,7 @@ func TestHandler_CreateStream(t *testing.T) {
 
 	creatorUUID := uuid.New().String()
 	companionUUID := uuid.New().String()
+	streamID := uuid.New().String()
 
 	t.Run("success", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -44,7 +53,7 @@ func TestHandler_CreateStream(t *testing.T) {
 		mockValidator := NewMockValidator(ctrl)
 		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
 
-		handler := New(mockRepo, mockUserClient, nil, mockValidator, nil)
+		handler := New(mockRepo, mockUserClient, nil, nil, mockValidator, nil, nil, &config.Config{})
 
 		mockLogger.EXPECT().AddFuncName("CreateStream")
 		mockValidator.EXPECT().ValidateCreateStream(gomock.Any(), creatorUUID).Return(nil)
@@ -68,9 +77,28 @@ func TestHandler_CreateStream(t *testing.T) {
 			}, nil)
 
 		mockRepo.EXPECT().AddNewUser(gomock.Any(), gomock.Any()).Return(nil).Times(2)
-		mockRepo.EXPECT().CreateStream(gomock.Any(), "private", gomock.Any(), creatorUUID).Return("test-stream-id", nil)
+		mockRepo.EXPECT().GetOrCreatePrivateStream(gomock.Any(), "chat metadata", creatorUUID, model.PrivatePair(uuid.MustParse(creatorUUID), uuid.MustParse(companionUUID))).
+			Return(streamID, true, nil)
 		mockRepo.EXPECT().AddStreamMembers(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-		mockRepo.EXPECT().AddUserSubscriptions(gomock.Any(), gomock.Any()).Return(nil)
+		mockRepo.EXPECT().AddUserSubscriptions(gomock.Any(), []model.UserSubscription{
+			{UserID: creatorUUID, Channel: streamID},
+			{UserID: creatorUUID, Channel: model.PersonalChannel(creatorUUID)},
+			{UserID: companionUUID, Channel: streamID},
+			{UserID: companionUUID, Channel: model.PersonalChannel(companionUUID)},
+		}).Return(nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), creatorUUID).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(creatorUUID), gomock.Any()).Return(nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(companionUUID), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+				event := data.(model.EventEnvelope)
+				assert.Equal(t, model.StreamAddedEvent, event.Type)
+				payload := event.Payload.(model.StreamAddedEventPayload)
+				assert.Equal(t, streamID, payload.StreamID.String())
+				assert.Equal(t, model.PrivateStreamType, payload.StreamType)
+				assert.Equal(t, model.MemberRole, payload.Role)
+				assert.Equal(t, creatorUUID, payload.Actor.ID.String())
+				return nil
+			})
 
 		requestBody := api.CreateStreamRequest{
 			Users: []api.ChatUser{
@@ -100,7 +128,122 @@ func TestHandler_CreateStream(t *testing.T) {
 		var response api.CreateStreamResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Equal(t, "test-stream-id", response.Id)
+		assert.Equal(t, streamID, response.Id)
+	})
+
+	t.Run("private_already_exists", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockUserClient := NewMockUserClient(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, mockUserClient, nil, nil, mockValidator, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("CreateStream")
+		mockValidator.EXPECT().ValidateCreateStream(gomock.Any(), creatorUUID).Return(nil)
+
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+
+		mockUserClient.EXPECT().GetUserInfoByUUID(gomock.Any(), gomock.Any()).
+			Return(&model.StreamMemberParams{}, nil).Times(2)
+		mockRepo.EXPECT().AddNewUser(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		// пара передаётся в каноническом виде независимо от того, кто создаёт стрим и как записан UUID
+		mockRepo.EXPECT().GetOrCreatePrivateStream(gomock.Any(), "chat metadata", creatorUUID, model.PrivatePair(uuid.MustParse(companionUUID), uuid.MustParse(creatorUUID))).
+			Return(streamID, false, nil)
+
+		requestBody := api.CreateStreamRequest{
+			Users: []api.ChatUser{
+				{Id: "urn:uuid:" + strings.ToUpper(companionUUID)},
+			},
+			Type:            "private",
+			ChatMetadata:    "chat metadata",
+			CreatorMetadata: "creator metadata",
+		}
+
+		bodyBytes, _ := json.Marshal(requestBody)
+		req := httptest.NewRequest(http.MethodPost, "/api/chat/streams", bytes.NewReader(bodyBytes))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, creatorUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.CreateStream(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.CreateStreamResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, streamID, response.Id)
+	})
+
+	t.Run("success_group", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockUserClient := NewMockUserClient(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, mockUserClient, nil, nil, mockValidator, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("CreateStream")
+		mockValidator.EXPECT().ValidateCreateStream(gomock.Any(), creatorUUID).Return(nil)
+
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+
+		mockUserClient.EXPECT().GetUserInfoByUUID(gomock.Any(), gomock.Any()).
+			Return(&model.StreamMemberParams{Nickname: "test_user", AvatarURL: "test_avatar"}, nil).Times(3)
+
+		mockRepo.EXPECT().AddNewUser(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+		mockRepo.EXPECT().CreateStream(gomock.Any(), "group", `{"title":"Team","avatar_url":"team.png"}`, creatorUUID).
+			Return(streamID, nil)
+		mockRepo.EXPECT().AddStreamMembers(gomock.Any(), streamID, gomock.Len(3)).Return(nil)
+		mockRepo.EXPECT().AddUserSubscriptions(gomock.Any(), gomock.Len(6)).Return(nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), creatorUUID).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+		requestBody := api.CreateStreamRequest{
+			Users: []api.ChatUser{
+				{Id: companionUUID},
+				{Id: uuid.New().String()},
+			},
+			Type:            "group",
+			Title:           stringPtr(" Team "),
+			AvatarUrl:       stringPtr("team.png"),
+			ChatMetadata:    "chat metadata",
+			CreatorMetadata: "creator metadata",
+		}
+
+		bodyBytes, _ := json.Marshal(requestBody)
+		req := httptest.NewRequest(http.MethodPost, "/api/chat/streams", bytes.NewReader(bodyBytes))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, creatorUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.CreateStream(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.CreateStreamResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, streamID, response.Id)
 	})
 
 	t.Run("invalid_json", func(t *testing.T) {
@@ -112,7 +255,7 @@ func TestHandler_CreateStream(t *testing.T) {
 		mockValidator := NewMockValidator(ctrl)
 		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
 
-		handler := New(mockRepo, mockUserClient, nil, mockValidator, nil)
+		handler := New(mockRepo, mockUserClient, nil, nil, mockValidator, nil, nil, &config.Config{})
 
 		mockLogger.EXPECT().AddFuncName("CreateStream")
 		mockLogger.EXPECT().Error(gomock.Any())
@@ -154,7 +297,7 @@ func TestHandler_SendMessage(t *testing.T) {
 		mockCentrifuge := NewMockCetrifugeClient(ctrl)
 		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
 
-		handler := New(mockRepo, mockUserClient, mockCentrifuge, mockValidator, nil)
+		handler := New(mockRepo, mockUserClient, mockCentrifuge, nil, mockValidator, nil, nil, &config.Config{})
 
 		mockLogger.EXPECT().AddFuncName("SendMessage")
 		mockValidator.EXPECT().ValidateSendMessage(gomock.Any()).Return(nil)
@@ -163,9 +306,51 @@ func TestHandler_SendMessage(t *testing.T) {
 			return fn(ctx)
 		}).AnyTimes()
 
-		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, senderUUID).Return(true, nil)
-		mockRepo.EXPECT().SaveMessage(gomock.Any(), gomock.Any()).Return(nil)
-		mockCentrifuge.EXPECT().Publish(gomock.Any(), streamID, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, senderUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.PrivateStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		sentAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		// время отправки задаёт БД
+		mockRepo.EXPECT().SaveMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *model.Message) error {
+			message.SentAt = sentAt
+			return nil
+		})
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), senderUUID).Return(&model.UserSnapshot{
+			ID:        uuid.MustParse(senderUUID),
+			Nickname:  "sender",
+			AvatarURL: "https://example.com/avatar.png",
+		}, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+			event, ok := data.(model.EventEnvelope)
+			require.True(t, ok)
+			assert.Equal(t, model.MessageCreatedEvent, event.Type)
+			assert.Equal(t, model.EventVersion, event.Version)
+			payload, ok := event.Payload.(model.MessageEventPayload)
+			require.True(t, ok)
+			assert.Equal(t, "sender", payload.Sender.Nickname)
+			assert.Equal(t, "Hello world", payload.Message.Content)
+			assert.Equal(t, sentAt, payload.Message.SentAt)
+			return nil
+		})
+		companionID := uuid.New()
+		mockRepo.EXPECT().GetStreamPersonalChannels(gomock.Any(), streamID, nil).Return([]model.PersonalChannelMember{
+			{UserID: uuid.MustParse(senderUUID), Channel: model.PersonalChannel(senderUUID)},
+			{UserID: companionID, Channel: model.PersonalChannel(companionID.String()), UnreadCount: 4},
+		}, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(senderUUID), gomock.Any()).Return(nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(companionID.String()), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+			event, ok := data.(model.EventEnvelope)
+			require.True(t, ok)
+			assert.Equal(t, model.StreamUpdatedEvent, event.Type)
+			payload, ok := event.Payload.(model.StreamUpdatedEventPayload)
+			require.True(t, ok)
+			assert.Equal(t, int64(4), payload.UnreadCount)
+			assert.NotNil(t, payload.LastMessageID)
+			assert.NotNil(t, payload.LastMessageAt)
+			return nil
+		})
 
 		requestBody := api.SendMessageRequest{
 			Content:     "Hello world",
@@ -197,27 +382,64 @@ func TestHandler_SendMessage(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 		assert.NotEmpty(t, response.MessageId)
-		assert.NotEmpty(t, response.SentAt)
+		assert.Equal(t, sentAt.Format(time.RFC3339), response.SentAt)
 	})
 
-	t.Run("no_senderID", func(t *testing.T) {
+	t.Run("success_media", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		mockRepo := NewMockDBRepo(ctrl)
-		mockUserClient := NewMockUserClient(ctrl)
 		mockValidator := NewMockValidator(ctrl)
 		mockCentrifuge := NewMockCetrifugeClient(ctrl)
 		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
 
-		handler := New(mockRepo, mockUserClient, mockCentrifuge, mockValidator, nil)
+		handler := New(mockRepo, nil, mockCentrifuge, nil, mockValidator, nil, nil, &config.Config{})
 
 		mockLogger.EXPECT().AddFuncName("SendMessage")
-		mockLogger.EXPECT().Error("failed to get sender ID")
+		mockValidator.EXPECT().ValidateSendMessage(gomock.Any()).Return(nil)
+
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
 
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, senderUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		thumbnailKey := "voice/1.ogg_thumb"
+		mockRepo.EXPECT().GetAttachment(gomock.Any(), "voice/1.ogg").Return(&model.Attachment{
+			StorageKey:   "voice/1.ogg",
+			UploaderID:   uuid.MustParse(senderUUID),
+			MimeType:     "audio/ogg",
+			Size:         2048,
+			ThumbnailKey: &thumbnailKey,
+		}, nil)
+		mockRepo.EXPECT().SaveMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, msg *model.Message) error {
+			require.NotNil(t, msg.Media)
+			assert.Equal(t, thumbnailKey, msg.Media.ThumbnailKey)
+			assert.Equal(t, model.SpeechMessageType, msg.Type)
+			assert.Equal(t, "voice/1.ogg", msg.Media.StorageKey)
+			assert.Equal(t, int64(3200), msg.Media.DurationMs)
+			assert.Equal(t, []int32{0, 128, 255}, msg.Media.Waveform)
+			return nil
+		})
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().GetStreamPersonalChannels(gomock.Any(), streamID, nil).Return(nil, nil)
+
+		duration := int64(3200)
+		waveform := []int32{0, 128, 255}
 		requestBody := api.SendMessageRequest{
-			Content:     "Hello",
-			MessageType: "text",
+			MessageType: model.SpeechMessageType,
+			Media: &api.MessageMedia{
+				StorageKey: "voice/1.ogg",
+				MimeType:   "audio/ogg",
+				Size:       2048,
+				DurationMs: &duration,
+				Waveform:   &waveform,
+			},
 		}
 
 		bodyBytes, _ := json.Marshal(requestBody)
@@ -225,138 +447,3338 @@ func TestHandler_SendMessage(t *testing.T) {
 
 		reqCtx := req.Context()
 		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, senderUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
 		req = req.WithContext(reqCtx)
 
-		req.Header.Set("Content-Type", "application/json")
-
 		w := httptest.NewRecorder()
 
-		rctx := chi.NewRouteContext()
-		rctx.URLParams.Add("stream_id", streamID)
-		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
-
 		handler.SendMessage(w, req, streamID)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
-
-		var errorResp api.Error
-		err := json.Unmarshal(w.Body.Bytes(), &errorResp)
-		require.NoError(t, err)
-		assert.Contains(t, errorResp.Error, "failed to get sender ID")
+		assert.Equal(t, http.StatusOK, w.Code)
 	})
-}
-
-func TestHandler_GetPrivateStreams(t *testing.T) {
-	t.Parallel()
 
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	t.Run("foreign_attachment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-	mockRepo := NewMockDBRepo(ctrl)
-	mockUserClient := NewMockUserClient(ctrl)
-	mockValidator := NewMockValidator(ctrl)
-	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
 
-	userUUID := uuid.New().String()
+		handler := New(mockRepo, nil, nil, nil, mockValidator, nil, nil, &config.Config{})
 
-	handler := New(mockRepo, mockUserClient, nil, mockValidator, nil)
+		mockLogger.EXPECT().AddFuncName("SendMessage")
+		mockLogger.EXPECT().Error(gomock.Any()).Times(2)
+		mockValidator.EXPECT().ValidateSendMessage(gomock.Any()).Return(nil)
 
-	t.Run("success", func(t *testing.T) {
-		mockLogger.EXPECT().AddFuncName("GetPrivateStreams")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
 
-		ptr := "Hello there!"
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, senderUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetAttachment(gomock.Any(), "files/report.pdf").Return(&model.Attachment{
+			StorageKey: "files/report.pdf",
+			UploaderID: uuid.New(),
+			MimeType:   "application/pdf",
+			Size:       4096,
+		}, nil)
 
-		expectedStreams := &model.PrivateStreamPreviewList{
-			{
-				StreamID:             uuid.New().String(),
-				StreamName:           "John Doe",
-				AvatarURL:            "avatar.jpg",
-				LastMessageContent:   &ptr,
-				LastMessageTimestamp: func() *time.Time { t := time.Now().Add(-10 * time.Minute); return &t }(),
+		requestBody := api.SendMessageRequest{
+			MessageType: model.FileMessageType,
+			Media: &api.MessageMedia{
+				StorageKey: "files/report.pdf",
+				MimeType:   "application/pdf",
+				Size:       4096,
 			},
 		}
 
-		mockRepo.EXPECT().GetPrivateStreams(gomock.Any(), userUUID).Return(expectedStreams, nil)
-
-		req := httptest.NewRequest(http.MethodGet, "/api/chat/streams/private", nil)
+		bodyBytes, _ := json.Marshal(requestBody)
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), bytes.NewReader(bodyBytes))
 
 		reqCtx := req.Context()
 		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
-		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, senderUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
 		req = req.WithContext(reqCtx)
 
 		w := httptest.NewRecorder()
-		handler.GetPrivateStreams(w, req)
 
-		assert.Equal(t, http.StatusOK, w.Code)
+		handler.SendMessage(w, req, streamID)
 
-		var response api.GetPrivateStreamsResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Len(t, response.Streams, 1)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
-}
-
-func TestHandler_GetStreamRecentMessages(t *testing.T) {
-	t.Parallel()
 
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	t.Run("channel_subscriber_forbidden", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-	mockRepo := NewMockDBRepo(ctrl)
-	mockUserClient := NewMockUserClient(ctrl)
-	mockValidator := NewMockValidator(ctrl)
-	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockCentrifuge := NewMockCetrifugeClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
 
-	userUUID := uuid.New().String()
-	streamID := uuid.New().String()
+		handler := New(mockRepo, nil, mockCentrifuge, nil, mockValidator, nil, nil, &config.Config{})
 
-	handler := New(mockRepo, mockUserClient, nil, mockValidator, nil)
+		mockLogger.EXPECT().AddFuncName("SendMessage")
+		mockLogger.EXPECT().Error(gomock.Any()).Times(2)
+		mockValidator.EXPECT().ValidateSendMessage(gomock.Any()).Return(nil)
 
-	t.Run("success", func(t *testing.T) {
-		mockLogger.EXPECT().AddFuncName("GetStreamRecentMessages")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, senderUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.ChannelStreamType,
+			Role:       model.MemberRole,
+		}, nil)
 
-		expectedMessages := &model.MessageList{
-			{
-				ID:        uuid.New(),
-				StreamID:  uuid.MustParse(streamID),
-				SenderID:  uuid.New(),
-				Type:      "text",
-				Content:   "message 1",
-				RootID:    nil,
-				ParentID:  nil,
-				SentAt:    time.Now().Add(-10 * time.Minute),
-				UpdatedAt: nil,
-			},
+		requestBody := api.SendMessageRequest{
+			Content:     "Hello",
+			MessageType: "text",
 		}
 
-		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
-		mockRepo.EXPECT().GetStreamRecentMessages(gomock.Any(), streamID, "", int32(20)).Return(expectedMessages, nil)
-
-		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), nil)
+		bodyBytes, _ := json.Marshal(requestBody)
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), bytes.NewReader(bodyBytes))
 
 		reqCtx := req.Context()
 		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
-		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, senderUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
 		req = req.WithContext(reqCtx)
 
 		w := httptest.NewRecorder()
+		handler.SendMessage(w, req, streamID)
 
-		rctx := chi.NewRouteContext()
-		rctx.URLParams.Add("stream_id", streamID)
-		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
 
-		handler.GetStreamRecentMessages(w, req, streamID, api.GetStreamRecentMessagesParams{})
+	t.Run("parent_from_other_stream", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
 
-		var response api.GetStreamRecentMessagesResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Len(t, response.Messages, 1)
+		handler := New(mockRepo, nil, nil, nil, mockValidator, nil, nil, &config.Config{})
+
+		parentID := uuid.New()
+
+		mockLogger.EXPECT().AddFuncName("SendMessage")
+		mockLogger.EXPECT().Error(gomock.Any()).Times(2)
+		mockValidator.EXPECT().ValidateSendMessage(gomock.Any()).Return(nil)
+
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, senderUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), parentID.String()).Return(&model.Message{
+			ID:       parentID,
+			StreamID: uuid.New(),
+			SenderID: uuid.New(),
+			SentAt:   time.Now(),
+		}, nil)
+
+		requestBody := api.SendMessageRequest{
+			Content:     "Reply",
+			MessageType: "text",
+			ParentId:    stringPtr(parentID.String()),
+		}
+
+		bodyBytes, _ := json.Marshal(requestBody)
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), bytes.NewReader(bodyBytes))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, senderUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.SendMessage(w, req, streamID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
-}
 
-func stringPtr(s string) *string {
-	return &s
+	t.Run("parent_outside_thread", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, mockValidator, nil, nil, &config.Config{})
+
+		rootID := uuid.New()
+		otherRootID := uuid.New()
+		parentID := uuid.New()
+
+		mockLogger.EXPECT().AddFuncName("SendMessage")
+		mockLogger.EXPECT().Error(gomock.Any()).Times(2)
+		mockValidator.EXPECT().ValidateSendMessage(gomock.Any()).Return(nil)
+
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, senderUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), rootID.String()).Return(&model.Message{
+			ID:       rootID,
+			StreamID: uuid.MustParse(streamID),
+			SentAt:   time.Now(),
+		}, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), parentID.String()).Return(&model.Message{
+			ID:       parentID,
+			StreamID: uuid.MustParse(streamID),
+			RootID:   &otherRootID,
+			SentAt:   time.Now(),
+		}, nil)
+
+		requestBody := api.SendMessageRequest{
+			Content:     "Reply",
+			MessageType: "text",
+			RootId:      stringPtr(rootID.String()),
+			ParentId:    stringPtr(parentID.String()),
+		}
+
+		bodyBytes, _ := json.Marshal(requestBody)
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), bytes.NewReader(bodyBytes))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, senderUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.SendMessage(w, req, streamID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("parent_reply_inherits_root", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, mockValidator, nil, nil, &config.Config{})
+
+		rootID := uuid.New()
+		parentID := uuid.New()
+
+		mockLogger.EXPECT().AddFuncName("SendMessage")
+		mockLogger.EXPECT().Error(gomock.Any()).Times(2)
+		mockValidator.EXPECT().ValidateSendMessage(gomock.Any()).Return(nil)
+
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, senderUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), parentID.String()).Return(&model.Message{
+			ID:       parentID,
+			StreamID: uuid.MustParse(streamID),
+			RootID:   &rootID,
+			SentAt:   time.Now(),
+		}, nil)
+		// сохранение обрывает транзакцию: проверяем только, в какой тред попал ответ
+		mockRepo.EXPECT().SaveMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *model.Message) error {
+			require.NotNil(t, message.RootID)
+			assert.Equal(t, rootID, *message.RootID)
+			assert.Equal(t, parentID, *message.ParentID)
+			return errors.New("db error")
+		})
+
+		requestBody := api.SendMessageRequest{
+			Content:     "Reply",
+			MessageType: "text",
+			ParentId:    stringPtr(parentID.String()),
+		}
+
+		bodyBytes, _ := json.Marshal(requestBody)
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), bytes.NewReader(bodyBytes))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, senderUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.SendMessage(w, req, streamID)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("no_senderID", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockUserClient := NewMockUserClient(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockCentrifuge := NewMockCetrifugeClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, mockUserClient, mockCentrifuge, nil, mockValidator, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("SendMessage")
+		mockLogger.EXPECT().Error("failed to get sender ID")
+
+		requestBody := api.SendMessageRequest{
+			Content:     "Hello",
+			MessageType: "text",
+		}
+
+		bodyBytes, _ := json.Marshal(requestBody)
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), bytes.NewReader(bodyBytes))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		req = req.WithContext(reqCtx)
+
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("stream_id", streamID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		handler.SendMessage(w, req, streamID)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var errorResp api.Error
+		err := json.Unmarshal(w.Body.Bytes(), &errorResp)
+		require.NoError(t, err)
+		assert.Contains(t, errorResp.Error, "failed to get sender ID")
+	})
+}
+
+func TestHandler_EditMessage(t *testing.T) {
+	t.Parallel()
+
+	senderUUID := uuid.New().String()
+	streamID := uuid.New().String()
+	messageID := uuid.New()
+
+	cfg := &config.Config{Messages: config.Messages{EditWindow: time.Hour}}
+
+	newRequest := func(mockLogger *logger_lib.MockLoggerInterface, mockRepo *MockDBRepo, userUUID string) *http.Request {
+		bodyBytes, _ := json.Marshal(api.EditMessageRequest{Content: "edited"})
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/chat/streams/%s/messages/%s", streamID, messageID), bytes.NewReader(bodyBytes))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		return req.WithContext(reqCtx)
+	}
+
+	storedMessage := func(sentAt time.Time) *model.Message {
+		return &model.Message{
+			ID:       messageID,
+			StreamID: uuid.MustParse(streamID),
+			SenderID: uuid.MustParse(senderUUID),
+			Type:     model.TextMessageType,
+			Content:  "original",
+			SentAt:   sentAt,
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockCentrifuge := NewMockCetrifugeClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, mockCentrifuge, nil, mockValidator, nil, nil, cfg)
+
+		updatedAt := time.Now()
+
+		mockLogger.EXPECT().AddFuncName("EditMessage")
+		mockValidator.EXPECT().ValidateEditMessage(gomock.Any()).Return(nil)
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, gomock.Any()).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), messageID.String()).Return(storedMessage(time.Now().Add(-time.Minute)), nil)
+		mockRepo.EXPECT().UpdateMessageContent(gomock.Any(), messageID.String(), "edited").Return(updatedAt, nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+			event, ok := data.(model.EventEnvelope)
+			require.True(t, ok)
+			assert.Equal(t, model.MessageEditedEvent, event.Type)
+			return nil
+		})
+
+		w := httptest.NewRecorder()
+		handler.EditMessage(w, newRequest(mockLogger, mockRepo, senderUUID), streamID, messageID.String())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.EditMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, messageID.String(), response.MessageId)
+		assert.Equal(t, updatedAt.Format(time.RFC3339), response.UpdatedAt)
+	})
+
+	t.Run("not_sender", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, mockValidator, nil, nil, cfg)
+
+		mockLogger.EXPECT().AddFuncName("EditMessage")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockValidator.EXPECT().ValidateEditMessage(gomock.Any()).Return(nil)
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, gomock.Any()).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), messageID.String()).Return(storedMessage(time.Now()), nil)
+
+		w := httptest.NewRecorder()
+		handler.EditMessage(w, newRequest(mockLogger, mockRepo, uuid.New().String()), streamID, messageID.String())
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("edit_window_expired", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, mockValidator, nil, nil, cfg)
+
+		mockLogger.EXPECT().AddFuncName("EditMessage")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockValidator.EXPECT().ValidateEditMessage(gomock.Any()).Return(nil)
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, gomock.Any()).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), messageID.String()).Return(storedMessage(time.Now().Add(-2*time.Hour)), nil)
+
+		w := httptest.NewRecorder()
+		handler.EditMessage(w, newRequest(mockLogger, mockRepo, senderUUID), streamID, messageID.String())
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var errorResp api.Error
+		err := json.Unmarshal(w.Body.Bytes(), &errorResp)
+		require.NoError(t, err)
+		assert.Contains(t, errorResp.Error, "edit window has expired")
+	})
+
+	t.Run("read_only", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, mockValidator, nil, nil, cfg)
+
+		mockLogger.EXPECT().AddFuncName("EditMessage")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockValidator.EXPECT().ValidateEditMessage(gomock.Any()).Return(nil)
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, senderUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.ReadOnlyRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.EditMessage(w, newRequest(mockLogger, mockRepo, senderUUID), streamID, messageID.String())
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestHandler_DeleteMessage(t *testing.T) {
+	t.Parallel()
+
+	senderUUID := uuid.New().String()
+	streamID := uuid.New().String()
+	messageID := uuid.New()
+
+	newRequest := func(mockLogger *logger_lib.MockLoggerInterface, mockRepo *MockDBRepo, userUUID string) *http.Request {
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/chat/streams/%s/messages/%s", streamID, messageID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		return req.WithContext(reqCtx)
+	}
+
+	storedMessage := &model.Message{
+		ID:       messageID,
+		StreamID: uuid.MustParse(streamID),
+		SenderID: uuid.MustParse(senderUUID),
+		Type:     model.TextMessageType,
+		Content:  "to be deleted",
+		SentAt:   time.Now(),
+	}
+	membership := &model.StreamMembership{StreamID: streamID, StreamType: model.GroupStreamType, Role: model.MemberRole}
+
+	t.Run("self", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		otherUUID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("DeleteMessage")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, otherUUID).Return(membership, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), messageID.String()).Return(storedMessage, nil)
+		mockRepo.EXPECT().HideMessage(gomock.Any(), messageID.String(), otherUUID).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.DeleteMessage(w, newRequest(mockLogger, mockRepo, otherUUID), streamID, messageID.String(), api.DeleteMessageParams{Format: api.Self})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockCentrifuge := NewMockCetrifugeClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, mockCentrifuge, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("DeleteMessage")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, senderUUID).Return(membership, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), messageID.String()).Return(storedMessage, nil)
+		mockRepo.EXPECT().DeleteMessage(gomock.Any(), messageID.String(), senderUUID).Return(time.Now(), nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+			event, ok := data.(model.EventEnvelope)
+			require.True(t, ok)
+			assert.Equal(t, model.MessageDeletedEvent, event.Type)
+			return nil
+		})
+
+		w := httptest.NewRecorder()
+		handler.DeleteMessage(w, newRequest(mockLogger, mockRepo, senderUUID), streamID, messageID.String(), api.DeleteMessageParams{Format: api.All})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("all_not_sender", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		otherUUID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("DeleteMessage")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, otherUUID).Return(membership, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), messageID.String()).Return(storedMessage, nil)
+
+		w := httptest.NewRecorder()
+		handler.DeleteMessage(w, newRequest(mockLogger, mockRepo, otherUUID), streamID, messageID.String(), api.DeleteMessageParams{Format: api.All})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("all_by_admin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		adminUUID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("DeleteMessage")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, adminUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.AdminRole,
+		}, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), messageID.String()).Return(storedMessage, nil)
+		mockRepo.EXPECT().DeleteMessage(gomock.Any(), messageID.String(), adminUUID).Return(time.Now(), nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), adminUUID).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, gomock.Any()).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.DeleteMessage(w, newRequest(mockLogger, mockRepo, adminUUID), streamID, messageID.String(), api.DeleteMessageParams{Format: api.All})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestHandler_GetStreams(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	companionUUID := uuid.New().String()
+	privateStreamID := uuid.New().String()
+	groupStreamID := uuid.New().String()
+	messageID := uuid.New().String()
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	newRequest := func(mockLogger *logger_lib.MockLoggerInterface) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/chat/streams", nil)
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("success_with_next_page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetStreams")
+
+		content := "hello"
+		nickname := "alice"
+		previews := &model.StreamPreviewList{
+			{
+				StreamID:                  privateStreamID,
+				StreamType:                model.PrivateStreamType,
+				StreamName:                nickname,
+				Role:                      model.MemberRole,
+				CompanionID:               &companionUUID,
+				LastMessageID:             &messageID,
+				LastMessageContent:        &content,
+				LastMessageSenderID:       &companionUUID,
+				LastMessageSenderNickname: &nickname,
+				LastMessageTimestamp:      &at,
+				ActivityAt:                at,
+				UnreadCount:               2,
+				Muted:                     true,
+				NotifyLevel:               model.MentionsNotifyLevel,
+				MutedUntil:                &at,
+			},
+			{
+				StreamID:   groupStreamID,
+				StreamType: model.GroupStreamType,
+				StreamName: "team",
+				Role:       model.OwnerRole,
+				ActivityAt: at.Add(-time.Hour),
+			},
+			{
+				StreamID:   uuid.New().String(),
+				StreamType: model.GroupStreamType,
+				ActivityAt: at.Add(-2 * time.Hour),
+			},
+		}
+
+		limit := 2
+		mockRepo.EXPECT().GetStreams(gomock.Any(), userUUID, "", nil, 3).Return(previews, nil)
+
+		w := httptest.NewRecorder()
+		handler.GetStreams(w, newRequest(mockLogger), api.GetStreamsParams{Limit: &limit})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetStreamsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Streams, 2)
+
+		private := response.Streams[0]
+		assert.Equal(t, api.StreamPreviewTypePrivate, private.Type)
+		assert.Equal(t, &companionUUID, private.CompanionId)
+		assert.True(t, private.Muted)
+		assert.Equal(t, api.NotifyMentions, private.NotifyLevel)
+		require.NotNil(t, private.MutedUntil)
+		assert.Equal(t, "2025-01-02T03:04:05Z", *private.MutedUntil)
+		assert.Equal(t, int64(2), private.UnreadCount)
+		require.NotNil(t, private.LastMessage)
+		assert.Equal(t, companionUUID, private.LastMessage.SenderId)
+		assert.Equal(t, &nickname, private.LastMessage.SenderNickname)
+		assert.Equal(t, "2025-01-02T03:04:05Z", private.LastMessage.SentAt)
+
+		group := response.Streams[1]
+		assert.Nil(t, group.LastMessage)
+		assert.Nil(t, group.CompanionId)
+		assert.Equal(t, "2025-01-02T02:04:05Z", group.LastActivityAt)
+
+		require.NotNil(t, response.NextCursor)
+		cursor, err := model.ParseStreamCursor(*response.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, groupStreamID, cursor.ID.String())
+		assert.True(t, at.Add(-time.Hour).Equal(cursor.ActivityAt))
+	})
+
+	t.Run("passes_cursor_and_type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetStreams")
+
+		cursor := model.StreamCursor{ActivityAt: at, ID: uuid.MustParse(groupStreamID)}
+		mockRepo.EXPECT().GetStreams(gomock.Any(), userUUID, model.GroupStreamType, &cursor, 21).Return(&model.StreamPreviewList{}, nil)
+
+		encoded := cursor.Encode()
+		groupType := api.GetStreamsParamsTypeGroup
+		w := httptest.NewRecorder()
+		handler.GetStreams(w, newRequest(mockLogger), api.GetStreamsParams{Type: &groupType, Cursor: &encoded})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetStreamsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response.Streams)
+		assert.Nil(t, response.NextCursor)
+	})
+
+	t.Run("clamps_limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetStreams")
+		mockRepo.EXPECT().GetStreams(gomock.Any(), userUUID, "", nil, maxStreamsLimit+1).Return(&model.StreamPreviewList{}, nil)
+
+		limit := 1_000_000
+		w := httptest.NewRecorder()
+		handler.GetStreams(w, newRequest(mockLogger), api.GetStreamsParams{Limit: &limit})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid_cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetStreams")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		invalid := "not-a-cursor"
+		w := httptest.NewRecorder()
+		handler.GetStreams(w, newRequest(mockLogger), api.GetStreamsParams{Cursor: &invalid})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid_type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetStreams")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		streamType := api.GetStreamsParamsType("direct")
+		w := httptest.NewRecorder()
+		handler.GetStreams(w, newRequest(mockLogger), api.GetStreamsParams{Type: &streamType})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_GetPrivateStreams(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockDBRepo(ctrl)
+	mockUserClient := NewMockUserClient(ctrl)
+	mockValidator := NewMockValidator(ctrl)
+	mockPresence := NewMockPresenceClient(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+	userUUID := uuid.New().String()
+	onlineCompanion := uuid.New().String()
+	offlineCompanion := uuid.New().String()
+
+	handler := New(mockRepo, mockUserClient, nil, mockPresence, mockValidator, nil, nil, &config.Config{})
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetPrivateStreams")
+
+		ptr := "Hello there!"
+		lastOnline := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		expectedStreams := &model.PrivateStreamPreviewList{
+			{
+				StreamID:             uuid.New().String(),
+				StreamName:           "John Doe",
+				AvatarURL:            "avatar.jpg",
+				CompanionID:          onlineCompanion,
+				LastMessageContent:   &ptr,
+				LastMessageTimestamp: func() *time.Time { t := time.Now().Add(-10 * time.Minute); return &t }(),
+			},
+			{
+				StreamID:            uuid.New().String(),
+				StreamName:          "Jane Doe",
+				CompanionID:         offlineCompanion,
+				CompanionLastOnline: &lastOnline,
+			},
+		}
+
+		mockRepo.EXPECT().GetPrivateStreams(gomock.Any(), userUUID).Return(expectedStreams, nil)
+		mockPresence.EXPECT().PresenceStatsBatch(gomock.Any(), []string{
+			model.PersonalChannel(onlineCompanion),
+			model.PersonalChannel(offlineCompanion),
+		}).Return([]*model.PresenceStats{{NumClients: 2, NumUsers: 1}, {}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/chat/streams/private", nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.GetPrivateStreams(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetPrivateStreamsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Len(t, response.Streams, 2)
+		assert.Equal(t, onlineCompanion, response.Streams[0].CompanionId)
+		assert.True(t, response.Streams[0].Online)
+		assert.Nil(t, response.Streams[0].LastOnline)
+		assert.False(t, response.Streams[1].Online)
+		require.NotNil(t, response.Streams[1].LastOnline)
+		assert.Equal(t, "2025-01-02T03:04:05Z", *response.Streams[1].LastOnline)
+	})
+
+	t.Run("presence_unavailable", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetPrivateStreams")
+		mockLogger.EXPECT().Warn(gomock.Any())
+
+		mockRepo.EXPECT().GetPrivateStreams(gomock.Any(), userUUID).Return(&model.PrivateStreamPreviewList{
+			{StreamID: uuid.New().String(), StreamName: "John Doe", CompanionID: onlineCompanion},
+		}, nil)
+		mockPresence.EXPECT().PresenceStatsBatch(gomock.Any(), []string{model.PersonalChannel(onlineCompanion)}).
+			Return(nil, errors.New("centrifugo unavailable"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/chat/streams/private", nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.GetPrivateStreams(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetPrivateStreamsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Len(t, response.Streams, 1)
+		assert.False(t, response.Streams[0].Online)
+	})
+}
+
+func TestHandler_GetGroupStreams(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockDBRepo(ctrl)
+	mockUserClient := NewMockUserClient(ctrl)
+	mockValidator := NewMockValidator(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+	userUUID := uuid.New().String()
+
+	handler := New(mockRepo, mockUserClient, nil, nil, mockValidator, nil, nil, &config.Config{})
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetGroupStreams")
+
+		expectedStreams := &model.GroupStreamPreviewList{
+			{
+				StreamID:   uuid.New().String(),
+				StreamName: "Team",
+				AvatarURL:  "team.png",
+			},
+		}
+
+		mockRepo.EXPECT().GetGroupStreams(gomock.Any(), userUUID).Return(expectedStreams, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/chat/streams/group", nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.GetGroupStreams(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetGroupStreamsResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Len(t, response.Streams, 1)
+		assert.Equal(t, "Team", response.Streams[0].StreamName)
+		assert.Nil(t, response.Streams[0].LastMessageTimestamp)
+	})
+}
+
+func TestHandler_SubscribeToStream(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockUserClient := NewMockUserClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, mockUserClient, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("SubscribeToStream")
+
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamType(gomock.Any(), streamID).Return(model.ChannelStreamType, nil)
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(nil, nil)
+		mockUserClient.EXPECT().GetUserInfoByUUID(gomock.Any(), userUUID).Return(&model.StreamMemberParams{UserID: userUUID}, nil)
+		mockRepo.EXPECT().AddNewUser(gomock.Any(), gomock.Any()).Return(nil)
+		mockRepo.EXPECT().AddStreamMembers(gomock.Any(), streamID, []model.StreamMember{
+			{UserID: userUUID, Metadata: "{}", Role: model.MemberRole},
+		}).Return(nil)
+		mockRepo.EXPECT().AddUserSubscriptions(gomock.Any(), []model.UserSubscription{
+			{UserID: userUUID, Channel: streamID},
+			{UserID: userUUID, Channel: model.PersonalChannel(userUUID)},
+		}).Return(nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), userUUID).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(userUUID), model.NewEventEnvelope(model.StreamAddedEvent, model.StreamAddedEventPayload{
+			StreamID:   uuid.MustParse(streamID),
+			StreamType: model.ChannelStreamType,
+			Role:       model.MemberRole,
+			Actor:      model.UserSnapshot{ID: uuid.MustParse(userUUID)},
+		})).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/subscribe", streamID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.SubscribeToStream(w, req, streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.SubscribeToStreamResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, streamID, response.StreamId)
+	})
+
+	t.Run("not_a_channel", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("SubscribeToStream")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamType(gomock.Any(), streamID).Return(model.PrivateStreamType, nil)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/subscribe", streamID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.SubscribeToStream(w, req, streamID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_GetStreamRecentMessages(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockDBRepo(ctrl)
+	mockUserClient := NewMockUserClient(ctrl)
+	mockValidator := NewMockValidator(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	handler := New(mockRepo, mockUserClient, nil, nil, mockValidator, nil, nil, &config.Config{})
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetStreamRecentMessages")
+
+		expectedMessages := &model.MessageList{
+			{
+				ID:        uuid.New(),
+				StreamID:  uuid.MustParse(streamID),
+				SenderID:  uuid.New(),
+				Type:      "text",
+				Content:   "message 1",
+				RootID:    nil,
+				ParentID:  nil,
+				SentAt:    time.Now().Add(-10 * time.Minute),
+				UpdatedAt: nil,
+			},
+		}
+
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockRepo.EXPECT().GetStreamRecentMessages(gomock.Any(), streamID, userUUID, nil, model.BeforeCursorDirection, int32(21)).Return(expectedMessages, nil)
+		mockRepo.EXPECT().GetStreamReadMarks(gomock.Any(), streamID, model.NewMessageCursor((*expectedMessages)[0])).Return(model.ReadMarkList{
+			{
+				StreamID:  uuid.MustParse(streamID),
+				UserID:    uuid.MustParse(userUUID),
+				MessageID: (*expectedMessages)[0].ID,
+				SentAt:    (*expectedMessages)[0].SentAt,
+				ReadAt:    time.Now(),
+			},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("stream_id", streamID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		handler.GetStreamRecentMessages(w, req, streamID, api.GetStreamRecentMessagesParams{})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetStreamRecentMessagesResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Len(t, response.Messages, 1)
+		assert.Equal(t, []string{userUUID}, response.Messages[0].ReadBy)
+		assert.Nil(t, response.NextCursor)
+	})
+
+	t.Run("after_cursor_with_next_page", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetStreamRecentMessages")
+
+		// у сообщений одинаковое время отправки, порядок задаёт id
+		sentAt := time.Now().Truncate(time.Second)
+		cursor := model.MessageCursor{SentAt: sentAt.UTC(), ID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}
+		first := model.Message{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), StreamID: uuid.MustParse(streamID), SentAt: sentAt}
+		second := model.Message{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003"), StreamID: uuid.MustParse(streamID), SentAt: sentAt}
+
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockRepo.EXPECT().GetStreamRecentMessages(gomock.Any(), streamID, userUUID, &cursor, model.AfterCursorDirection, int32(2)).
+			Return(&model.MessageList{first, second}, nil)
+		mockRepo.EXPECT().GetStreamReadMarks(gomock.Any(), streamID, model.NewMessageCursor(first)).Return(model.ReadMarkList{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), nil)
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+
+		encoded := cursor.Encode()
+		direction := api.After
+		limit := 1
+		handler.GetStreamRecentMessages(w, req, streamID, api.GetStreamRecentMessagesParams{
+			Cursor:    &encoded,
+			Direction: &direction,
+			Limit:     &limit,
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetStreamRecentMessagesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Messages, 1)
+		assert.Equal(t, first.ID.String(), response.Messages[0].Uuid)
+		require.NotNil(t, response.NextCursor)
+
+		next, err := model.ParseMessageCursor(*response.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, next.ID)
+		assert.True(t, sentAt.Equal(next.SentAt))
+	})
+
+	t.Run("invalid_cursor", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetStreamRecentMessages")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), nil)
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+
+		encoded := "not-a-cursor"
+		handler.GetStreamRecentMessages(w, req, streamID, api.GetStreamRecentMessagesParams{Cursor: &encoded})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("clamps_limit", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetStreamRecentMessages")
+
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockRepo.EXPECT().GetStreamRecentMessages(gomock.Any(), streamID, userUUID, nil, model.BeforeCursorDirection, int32(maxMessagesLimit+1)).
+			Return(&model.MessageList{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/messages", streamID), nil)
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		limit := math.MaxInt32
+		w := httptest.NewRecorder()
+		handler.GetStreamRecentMessages(w, req, streamID, api.GetStreamRecentMessagesParams{Limit: &limit})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestHandler_GetMessageContext(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	newRequest := func(mockLogger *logger_lib.MockLoggerInterface, messageID string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/messages/%s/context", streamID, messageID), nil)
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		now := time.Now()
+		message := func(offset time.Duration) model.Message {
+			return model.Message{ID: uuid.New(), StreamID: uuid.MustParse(streamID), SentAt: now.Add(offset)}
+		}
+		target := message(0)
+		older := model.MessageList{message(-time.Minute), message(-2 * time.Minute), message(-3 * time.Minute)}
+		newer := model.MessageList{message(time.Minute)}
+		cursor := model.NewMessageCursor(target)
+
+		mockLogger.EXPECT().AddFuncName("GetMessageContext")
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockRepo.EXPECT().GetStreamMessage(gomock.Any(), streamID, userUUID, target.ID.String()).Return(&target, nil)
+		mockRepo.EXPECT().GetStreamRecentMessages(gomock.Any(), streamID, userUUID, &cursor, model.BeforeCursorDirection, int32(3)).Return(&older, nil)
+		mockRepo.EXPECT().GetStreamRecentMessages(gomock.Any(), streamID, userUUID, &cursor, model.AfterCursorDirection, int32(3)).Return(&newer, nil)
+		// отметки нужны только начиная с самого старого сообщения, попавшего в ответ
+		mockRepo.EXPECT().GetStreamReadMarks(gomock.Any(), streamID, model.NewMessageCursor(older[1])).Return(model.ReadMarkList{}, nil)
+
+		w := httptest.NewRecorder()
+
+		limit := 2
+		handler.GetMessageContext(w, newRequest(mockLogger, target.ID.String()), streamID, target.ID.String(), api.GetMessageContextParams{Limit: &limit})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetMessageContextResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Messages, 4)
+		assert.Equal(t, older[1].ID.String(), response.Messages[0].Uuid)
+		assert.Equal(t, older[0].ID.String(), response.Messages[1].Uuid)
+		assert.Equal(t, target.ID.String(), response.Messages[2].Uuid)
+		assert.Equal(t, newer[0].ID.String(), response.Messages[3].Uuid)
+		assert.True(t, response.HasOlder)
+		assert.False(t, response.HasNewer)
+
+		beforeCursor, err := model.ParseMessageCursor(response.BeforeCursor)
+		require.NoError(t, err)
+		assert.Equal(t, older[1].ID, beforeCursor.ID)
+
+		afterCursor, err := model.ParseMessageCursor(response.AfterCursor)
+		require.NoError(t, err)
+		assert.Equal(t, newer[0].ID, afterCursor.ID)
+	})
+
+	t.Run("message_not_found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		messageID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("GetMessageContext")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockRepo.EXPECT().GetStreamMessage(gomock.Any(), streamID, userUUID, messageID).Return(nil, nil)
+
+		w := httptest.NewRecorder()
+
+		handler.GetMessageContext(w, newRequest(mockLogger, messageID), streamID, messageID, api.GetMessageContextParams{})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandler_GetMessageThread(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockDBRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+	rootID := uuid.New()
+
+	handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+	t.Run("success", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetMessageThread")
+
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), rootID.String()).Return(&model.Message{
+			ID:         rootID,
+			StreamID:   uuid.MustParse(streamID),
+			SenderID:   uuid.New(),
+			SentAt:     time.Now().Add(-time.Hour),
+			ReplyCount: 1,
+		}, nil)
+		mockRepo.EXPECT().GetThreadMessages(gomock.Any(), streamID, rootID.String(), userUUID, nil, int32(21)).Return(&model.MessageList{
+			{
+				ID:       uuid.New(),
+				StreamID: uuid.MustParse(streamID),
+				SenderID: uuid.New(),
+				Type:     model.TextMessageType,
+				Content:  "reply",
+				RootID:   &rootID,
+				SentAt:   time.Now(),
+			},
+		}, nil)
+		mockRepo.EXPECT().GetStreamReadMarks(gomock.Any(), streamID, gomock.Any()).Return(nil, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/messages/%s/thread", streamID, rootID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.GetMessageThread(w, req, streamID, rootID.String(), api.GetMessageThreadParams{})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetMessageThreadResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Len(t, response.Messages, 1)
+		assert.Equal(t, rootID.String(), *response.Messages[0].RootUuid)
+		assert.Nil(t, response.NextCursor)
+	})
+
+	t.Run("next_page", func(t *testing.T) {
+		sentAt := time.Now().Truncate(time.Microsecond).UTC()
+		cursor := model.MessageCursor{SentAt: sentAt, ID: uuid.New()}
+		newer := model.Message{ID: uuid.New(), StreamID: uuid.MustParse(streamID), RootID: &rootID, SentAt: sentAt.Add(-time.Minute)}
+		older := model.Message{ID: uuid.New(), StreamID: uuid.MustParse(streamID), RootID: &rootID, SentAt: sentAt.Add(-2 * time.Minute)}
+
+		mockLogger.EXPECT().AddFuncName("GetMessageThread")
+
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), rootID.String()).Return(&model.Message{
+			ID:       rootID,
+			StreamID: uuid.MustParse(streamID),
+			SentAt:   time.Now().Add(-time.Hour),
+		}, nil)
+		mockRepo.EXPECT().GetThreadMessages(gomock.Any(), streamID, rootID.String(), userUUID, &cursor, int32(2)).
+			Return(&model.MessageList{newer, older}, nil)
+		mockRepo.EXPECT().GetStreamReadMarks(gomock.Any(), streamID, model.NewMessageCursor(newer)).Return(nil, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/messages/%s/thread", streamID, rootID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		limit := 1
+		w := httptest.NewRecorder()
+		handler.GetMessageThread(w, req, streamID, rootID.String(), api.GetMessageThreadParams{
+			Cursor: stringPtr(cursor.Encode()),
+			Limit:  &limit,
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetMessageThreadResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.Len(t, response.Messages, 1)
+		require.NotNil(t, response.NextCursor)
+		assert.Equal(t, model.NewMessageCursor(newer).Encode(), *response.NextCursor)
+	})
+
+	t.Run("clamps_limit", func(t *testing.T) {
+		mockLogger.EXPECT().AddFuncName("GetMessageThread")
+
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), rootID.String()).Return(&model.Message{
+			ID:       rootID,
+			StreamID: uuid.MustParse(streamID),
+			SentAt:   time.Now().Add(-time.Hour),
+		}, nil)
+		mockRepo.EXPECT().GetThreadMessages(gomock.Any(), streamID, rootID.String(), userUUID, nil, int32(maxMessagesLimit+1)).
+			Return(&model.MessageList{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/messages/%s/thread", streamID, rootID), nil)
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		limit := math.MaxInt32
+		w := httptest.NewRecorder()
+		handler.GetMessageThread(w, req, streamID, rootID.String(), api.GetMessageThreadParams{Limit: &limit})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestHandler_GetStreamPresence(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockPresence := NewMockPresenceClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, mockPresence, nil, nil, nil, &config.Config{})
+
+		otherUUID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("GetStreamPresence")
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockPresence.EXPECT().Presence(gomock.Any(), streamID).Return(map[string]model.CentrifugoClientInfo{
+			"c1": {Client: "c1", User: userUUID},
+			"c2": {Client: "c2", User: userUUID},
+			"c3": {Client: "c3", User: otherUUID},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/presence", streamID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.GetStreamPresence(w, req, streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetStreamPresenceResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, 3, response.NumClients)
+		assert.Equal(t, 2, response.NumUsers)
+		assert.ElementsMatch(t, []string{userUUID, otherUUID}, response.UserIds)
+	})
+
+	t.Run("not_a_member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetStreamPresence")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(false, nil)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/streams/%s/presence", streamID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.GetStreamPresence(w, req, streamID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestHandler_AddStreamMembers(t *testing.T) {
+	t.Parallel()
+
+	ownerUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	newRequest := func(t *testing.T, body api.AddStreamMembersRequest, mockLogger *logger_lib.MockLoggerInterface, mockRepo *MockDBRepo) *http.Request {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/members", streamID), bytes.NewReader(data))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, ownerUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockUserClient := NewMockUserClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, mockUserClient, nil, nil, nil, nil, nil, &config.Config{})
+
+		newUUID := uuid.New().String()
+		existingUUID := uuid.New().String()
+		owner := model.UserSnapshot{ID: uuid.MustParse(ownerUUID), Nickname: "owner"}
+
+		mockLogger.EXPECT().AddFuncName("AddStreamMembers")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, ownerUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.OwnerRole,
+		}, nil)
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, newUUID).Return(false, nil)
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, existingUUID).Return(true, nil)
+		mockUserClient.EXPECT().GetUserInfoByUUID(gomock.Any(), newUUID).Return(&model.StreamMemberParams{UserID: newUUID}, nil)
+		mockRepo.EXPECT().AddNewUser(gomock.Any(), gomock.Any()).Return(nil)
+		mockRepo.EXPECT().AddStreamMembers(gomock.Any(), streamID, []model.StreamMember{
+			{UserID: newUUID, Metadata: "{}", Role: model.MemberRole},
+		}).Return(nil)
+		mockRepo.EXPECT().AddUserSubscriptions(gomock.Any(), []model.UserSubscription{
+			{UserID: newUUID, Channel: streamID},
+			{UserID: newUUID, Channel: model.PersonalChannel(newUUID)},
+		}).Return(nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), ownerUUID).Return(&owner, nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), newUUID).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(newUUID), model.NewEventEnvelope(model.StreamAddedEvent, model.StreamAddedEventPayload{
+			StreamID:   uuid.MustParse(streamID),
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+			Actor:      owner,
+		})).Return(nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, model.NewEventEnvelope(model.MemberJoinedEvent, model.MemberEventPayload{
+			StreamID: uuid.MustParse(streamID),
+			Member:   model.UserSnapshot{ID: uuid.MustParse(newUUID)},
+			Actor:    owner,
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.AddStreamMembers(w, newRequest(t, api.AddStreamMembersRequest{
+			Users: []api.ChatUser{{Id: newUUID}, {Id: existingUUID}, {Id: ownerUUID}},
+		}, mockLogger, mockRepo), streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.AddStreamMembersResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, []string{newUUID}, response.Added)
+	})
+
+	t.Run("not_an_admin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("AddStreamMembers")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, ownerUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.AddStreamMembers(w, newRequest(t, api.AddStreamMembersRequest{
+			Users: []api.ChatUser{{Id: uuid.New().String()}},
+		}, mockLogger, mockRepo), streamID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestHandler_RemoveStreamMember(t *testing.T) {
+	t.Parallel()
+
+	adminUUID := uuid.New().String()
+	memberUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	newRequest := func(mockLogger *logger_lib.MockLoggerInterface, mockRepo *MockDBRepo) *http.Request {
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/chat/streams/%s/members/%s", streamID, memberUUID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, adminUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		mockPresence := NewMockPresenceClient(ctrl)
+
+		handler := New(mockRepo, nil, nil, mockPresence, nil, nil, nil, &config.Config{})
+
+		admin := model.UserSnapshot{ID: uuid.MustParse(adminUUID), Nickname: "admin"}
+		member := model.UserSnapshot{ID: uuid.MustParse(memberUUID), Nickname: "member"}
+
+		mockLogger.EXPECT().AddFuncName("RemoveStreamMember")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, adminUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.AdminRole,
+		}, nil)
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, memberUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), adminUUID).Return(&admin, nil)
+		mockRepo.EXPECT().LeaveStream(gomock.Any(), streamID, memberUUID).Return(true, nil)
+		mockRepo.EXPECT().RemoveUserSubscription(gomock.Any(), memberUUID, streamID).Return(nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), memberUUID).Return(&member, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, model.NewEventEnvelope(model.MemberLeftEvent, model.MemberEventPayload{
+			StreamID: uuid.MustParse(streamID),
+			Member:   member,
+			Actor:    admin,
+		})).Return(nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(memberUUID), model.NewEventEnvelope(model.StreamRemovedEvent, model.StreamRemovedEventPayload{
+			StreamID: uuid.MustParse(streamID),
+			Actor:    admin,
+		})).Return(nil)
+		mockPresence.EXPECT().Unsubscribe(gomock.Any(), memberUUID, streamID).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.RemoveStreamMember(w, newRequest(mockLogger, mockRepo), streamID, memberUUID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("unsubscribe_failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		mockPresence := NewMockPresenceClient(ctrl)
+
+		handler := New(mockRepo, nil, nil, mockPresence, nil, nil, nil, &config.Config{})
+
+		admin := model.UserSnapshot{ID: uuid.MustParse(adminUUID), Nickname: "admin"}
+		member := model.UserSnapshot{ID: uuid.MustParse(memberUUID), Nickname: "member"}
+
+		mockLogger.EXPECT().AddFuncName("RemoveStreamMember")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, adminUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.AdminRole,
+		}, nil)
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, memberUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), adminUUID).Return(&admin, nil)
+		mockRepo.EXPECT().LeaveStream(gomock.Any(), streamID, memberUUID).Return(true, nil)
+		mockRepo.EXPECT().RemoveUserSubscription(gomock.Any(), memberUUID, streamID).Return(nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), memberUUID).Return(&member, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, model.NewEventEnvelope(model.MemberLeftEvent, model.MemberEventPayload{
+			StreamID: uuid.MustParse(streamID),
+			Member:   member,
+			Actor:    admin,
+		})).Return(nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(memberUUID), model.NewEventEnvelope(model.StreamRemovedEvent, model.StreamRemovedEventPayload{
+			StreamID: uuid.MustParse(streamID),
+			Actor:    admin,
+		})).Return(nil)
+		mockPresence.EXPECT().Unsubscribe(gomock.Any(), memberUUID, streamID).Return(errors.New("centrifugo unavailable"))
+		mockLogger.EXPECT().Warn(gomock.Any())
+
+		w := httptest.NewRecorder()
+		handler.RemoveStreamMember(w, newRequest(mockLogger, mockRepo), streamID, memberUUID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("admin_cannot_remove_owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("RemoveStreamMember")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, adminUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.AdminRole,
+		}, nil)
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, memberUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.OwnerRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.RemoveStreamMember(w, newRequest(mockLogger, mockRepo), streamID, memberUUID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestHandler_LeaveStream(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	newRequest := func(mockLogger *logger_lib.MockLoggerInterface, mockRepo *MockDBRepo) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/leave", streamID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		mockPresence := NewMockPresenceClient(ctrl)
+
+		handler := New(mockRepo, nil, nil, mockPresence, nil, nil, nil, &config.Config{})
+
+		user := model.UserSnapshot{ID: uuid.MustParse(userUUID), Nickname: "alice"}
+
+		mockLogger.EXPECT().AddFuncName("LeaveStream")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.ChannelStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), userUUID).Return(&user, nil)
+		mockRepo.EXPECT().LeaveStream(gomock.Any(), streamID, userUUID).Return(true, nil)
+		mockRepo.EXPECT().RemoveUserSubscription(gomock.Any(), userUUID, streamID).Return(nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, model.NewEventEnvelope(model.MemberLeftEvent, model.MemberEventPayload{
+			StreamID: uuid.MustParse(streamID),
+			Member:   user,
+			Actor:    user,
+		})).Return(nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(userUUID), gomock.Any()).Return(nil)
+		mockPresence.EXPECT().Unsubscribe(gomock.Any(), userUUID, streamID).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.LeaveStream(w, newRequest(mockLogger, mockRepo), streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.StreamMemberResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, userUUID, response.UserId)
+	})
+
+	t.Run("owner_cannot_leave", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("LeaveStream")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.OwnerRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.LeaveStream(w, newRequest(mockLogger, mockRepo), streamID)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("private_stream", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("LeaveStream")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.PrivateStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.LeaveStream(w, newRequest(mockLogger, mockRepo), streamID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_UpdateStream(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	newRequest := func(t *testing.T, body api.UpdateStreamRequest, mockLogger *logger_lib.MockLoggerInterface, mockRepo *MockDBRepo) *http.Request {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/chat/streams/%s", streamID), bytes.NewReader(data))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, mockValidator, nil, nil, &config.Config{})
+
+		metadata := &model.StreamMetadata{Title: "team", AvatarURL: "https://example.com/a.png"}
+
+		mockLogger.EXPECT().AddFuncName("UpdateStream")
+		mockValidator.EXPECT().ValidateUpdateStream(gomock.Any()).Return(nil)
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.ChannelStreamType,
+			Role:       model.AdminRole,
+		}, nil)
+		mockRepo.EXPECT().UpdateStreamMetadata(gomock.Any(), streamID, `{"title":"team"}`).Return(metadata, nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), userUUID).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, model.NewEventEnvelope(model.StreamEditedEvent, model.StreamEditedEventPayload{
+			StreamID: uuid.MustParse(streamID),
+			Metadata: *metadata,
+			Actor:    model.UserSnapshot{ID: uuid.MustParse(userUUID)},
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.UpdateStream(w, newRequest(t, api.UpdateStreamRequest{Title: stringPtr(" team ")}, mockLogger, mockRepo), streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.UpdateStreamResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "team", response.Title)
+		assert.Equal(t, &metadata.AvatarURL, response.AvatarUrl)
+	})
+
+	t.Run("member_cannot_edit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockValidator := NewMockValidator(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, mockValidator, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("UpdateStream")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockValidator.EXPECT().ValidateUpdateStream(gomock.Any()).Return(nil)
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.UpdateStream(w, newRequest(t, api.UpdateStreamRequest{Title: stringPtr("team")}, mockLogger, mockRepo), streamID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestHandler_SetStreamMemberRole(t *testing.T) {
+	t.Parallel()
+
+	ownerUUID := uuid.New().String()
+	memberUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	newRequest := func(t *testing.T, userUUID string, role api.SetStreamMemberRoleRequestRole, mockLogger *logger_lib.MockLoggerInterface, mockRepo *MockDBRepo) *http.Request {
+		data, err := json.Marshal(api.SetStreamMemberRoleRequest{Role: role})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/chat/streams/%s/members/%s/role", streamID, memberUUID), bytes.NewReader(data))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("owner_promotes_admin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		owner := model.UserSnapshot{ID: uuid.MustParse(ownerUUID), Nickname: "owner"}
+		member := model.UserSnapshot{ID: uuid.MustParse(memberUUID), Nickname: "member"}
+
+		mockLogger.EXPECT().AddFuncName("SetStreamMemberRole")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, ownerUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.OwnerRole,
+		}, nil)
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, memberUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), ownerUUID).Return(&owner, nil)
+		mockRepo.EXPECT().UpdateMemberRole(gomock.Any(), streamID, memberUUID, model.AdminRole).Return(nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), memberUUID).Return(&member, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, model.NewEventEnvelope(model.MemberRoleChangedEvent, model.MemberRoleChangedEventPayload{
+			StreamID: uuid.MustParse(streamID),
+			Member:   member,
+			Role:     model.AdminRole,
+			Actor:    owner,
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.SetStreamMemberRole(w, newRequest(t, ownerUUID, api.Admin, mockLogger, mockRepo), streamID, memberUUID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.StreamMemberRoleResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, model.AdminRole, response.Role)
+	})
+
+	t.Run("admin_cannot_promote_admin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		adminUUID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("SetStreamMemberRole")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, adminUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.AdminRole,
+		}, nil)
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, memberUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.SetStreamMemberRole(w, newRequest(t, adminUUID, api.Admin, mockLogger, mockRepo), streamID, memberUUID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("invalid_role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("SetStreamMemberRole")
+
+		w := httptest.NewRecorder()
+		handler.SetStreamMemberRole(w, newRequest(t, ownerUUID, api.SetStreamMemberRoleRequestRole(model.OwnerRole), mockLogger, nil), streamID, memberUUID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_TransferStreamOwnership(t *testing.T) {
+	t.Parallel()
+
+	ownerUUID := uuid.New().String()
+	adminUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	newRequest := func(t *testing.T, userUUID string, mockLogger *logger_lib.MockLoggerInterface, mockRepo *MockDBRepo) *http.Request {
+		data, err := json.Marshal(api.TransferStreamOwnershipRequest{UserId: adminUUID})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/owner", streamID), bytes.NewReader(data))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		owner := model.UserSnapshot{ID: uuid.MustParse(ownerUUID), Nickname: "owner"}
+
+		mockLogger.EXPECT().AddFuncName("TransferStreamOwnership")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, ownerUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.ChannelStreamType,
+			Role:       model.OwnerRole,
+		}, nil)
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, adminUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.ChannelStreamType,
+			Role:       model.AdminRole,
+		}, nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), ownerUUID).Return(&owner, nil)
+		gomock.InOrder(
+			mockRepo.EXPECT().UpdateMemberRole(gomock.Any(), streamID, ownerUUID, model.AdminRole).Return(nil),
+			mockRepo.EXPECT().UpdateMemberRole(gomock.Any(), streamID, adminUUID, model.OwnerRole).Return(nil),
+		)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), adminUUID).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, gomock.Any()).Return(nil).Times(2)
+
+		w := httptest.NewRecorder()
+		handler.TransferStreamOwnership(w, newRequest(t, ownerUUID, mockLogger, mockRepo), streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not_owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		otherUUID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("TransferStreamOwnership")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, otherUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.AdminRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.TransferStreamOwnership(w, newRequest(t, otherUUID, mockLogger, mockRepo), streamID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestHandler_SendTyping(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+
+	newRequest := func(streamID string, mockLogger *logger_lib.MockLoggerInterface) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/typing", streamID), nil)
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockCentrifuge := NewMockCetrifugeClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, mockCentrifuge, nil, nil, nil, nil, &config.Config{
+			Typing: config.Typing{Interval: time.Minute, TTL: 6 * time.Second},
+		})
+
+		streamID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("SendTyping")
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockCentrifuge.EXPECT().Publish(gomock.Any(), streamID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+				event, ok := data.(model.EventEnvelope)
+				require.True(t, ok)
+				assert.Equal(t, model.UserTypingEvent, event.Type)
+				return nil
+			})
+
+		w := httptest.NewRecorder()
+		handler.SendTyping(w, newRequest(streamID, mockLogger), streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.SendTypingResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Greater(t, response.ExpiresAt, time.Now().Unix())
+	})
+
+	t.Run("rate_limited", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockCentrifuge := NewMockCetrifugeClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, mockCentrifuge, nil, nil, nil, nil, &config.Config{
+			Typing: config.Typing{Interval: time.Minute, TTL: 6 * time.Second},
+		})
+
+		streamID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("SendTyping").Times(2)
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.PrivateStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockCentrifuge.EXPECT().Publish(gomock.Any(), streamID, gomock.Any()).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.SendTyping(w, newRequest(streamID, mockLogger), streamID)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		handler.SendTyping(w, newRequest(streamID, mockLogger), streamID)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("not_a_member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		streamID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("SendTyping")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(nil, nil)
+
+		w := httptest.NewRecorder()
+		handler.SendTyping(w, newRequest(streamID, mockLogger), streamID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("channel_stream", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		streamID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("SendTyping")
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.ChannelStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.SendTyping(w, newRequest(streamID, mockLogger), streamID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("read_only", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		streamID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("SendTyping")
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.GroupStreamType,
+			Role:       model.ReadOnlyRole,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.SendTyping(w, newRequest(streamID, mockLogger), streamID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestHandler_MarkStreamRead(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+	messageID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockCentrifuge := NewMockCetrifugeClient(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, mockCentrifuge, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("MarkStreamRead")
+		mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockRepo.EXPECT().GetStreamMembership(gomock.Any(), streamID, userUUID).Return(&model.StreamMembership{
+			StreamID:   streamID,
+			StreamType: model.PrivateStreamType,
+			Role:       model.MemberRole,
+		}, nil)
+		mockRepo.EXPECT().GetMessage(gomock.Any(), messageID.String()).Return(&model.Message{
+			ID:       messageID,
+			StreamID: uuid.MustParse(streamID),
+			SenderID: uuid.New(),
+			SentAt:   time.Now(),
+		}, nil)
+		readAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockRepo.EXPECT().MarkStreamRead(gomock.Any(), streamID, userUUID, messageID.String()).Return(&readAt, nil)
+		mockRepo.EXPECT().GetUserSnapshot(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), streamID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, data interface{}) error {
+			payload := data.(model.EventEnvelope).Payload.(model.MessagesReadEventPayload)
+			// в событии то же время прочтения, что записано в БД
+			assert.Equal(t, readAt, payload.ReadMark.ReadAt)
+			return nil
+		})
+		mockRepo.EXPECT().GetStreamPersonalChannels(gomock.Any(), streamID, []string{userUUID}).Return([]model.PersonalChannelMember{
+			{UserID: uuid.MustParse(userUUID), Channel: model.PersonalChannel(userUUID)},
+		}, nil)
+		mockRepo.EXPECT().EnqueueOutbox(gomock.Any(), model.PersonalChannel(userUUID), model.NewEventEnvelope(model.StreamUpdatedEvent, model.StreamUpdatedEventPayload{
+			StreamID: uuid.MustParse(streamID),
+		})).Return(nil)
+
+		bodyBytes, _ := json.Marshal(api.MarkStreamReadRequest{MessageId: messageID.String()})
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/chat/streams/%s/read", streamID), bytes.NewReader(bodyBytes))
+
+		reqCtx := req.Context()
+		reqCtx = context.WithValue(reqCtx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		reqCtx = createTxContext(reqCtx, mockRepo)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.MarkStreamRead(w, req, streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.MarkStreamReadResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.True(t, response.Updated)
+	})
+}
+
+func TestHandler_StreamEvents(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+	sseConfig := &config.Config{
+		SSE: config.SSE{
+			PollInterval:      time.Millisecond,
+			HeartbeatInterval: time.Hour,
+			BatchSize:         10,
+		},
+	}
+
+	t.Run("resume_from_last_event_id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, sseConfig)
+
+		edited := model.Event{
+			ID:      42,
+			Xid:     1000,
+			Channel: streamID,
+			Type:    model.MessageEditedEvent,
+			Payload: json.RawMessage(`{"type":"message.edited","version":1,"payload":{"message":{"content":"hello"}}}`),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mockLogger.EXPECT().AddFuncName("StreamEvents")
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(true, nil)
+		gomock.InOrder(
+			mockRepo.EXPECT().GetEventsAfter(gomock.Any(), userUUID, []string{streamID}, model.EventCursor{Xid: 999, ID: 41}, 10).
+				Return([]model.Event{edited}, nil),
+			mockRepo.EXPECT().GetEventsAfter(gomock.Any(), userUUID, []string{streamID}, model.EventCursor{Xid: 1000, ID: 42}, 10).
+				DoAndReturn(func(context.Context, string, []string, model.EventCursor, int) ([]model.Event, error) {
+					cancel()
+					return nil, nil
+				}),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/chat/events?stream_id=%s", streamID), nil)
+		reqCtx := context.WithValue(ctx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.StreamEvents(w, req, api.StreamEventsParams{
+			StreamId:    &[]string{streamID},
+			LastEventID: stringPtr("999-41"),
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+		body := w.Body.String()
+		assert.Contains(t, body, "id: 1000-42\n")
+		assert.Contains(t, body, "event: message.edited\n")
+		assert.Contains(t, body, `"content":"hello"`)
+	})
+
+	t.Run("starts_from_last_event", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, sseConfig)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mockLogger.EXPECT().AddFuncName("StreamEvents")
+		mockRepo.EXPECT().GetLastEventCursor(gomock.Any()).Return(model.EventCursor{Xid: 500, ID: 7}, nil)
+		mockRepo.EXPECT().GetEventsAfter(gomock.Any(), userUUID, nil, model.EventCursor{Xid: 500, ID: 7}, 10).
+			DoAndReturn(func(context.Context, string, []string, model.EventCursor, int) ([]model.Event, error) {
+				cancel()
+				return nil, nil
+			})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/chat/events", nil)
+		reqCtx := context.WithValue(ctx, config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.StreamEvents(w, req, api.StreamEventsParams{})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not_a_member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, sseConfig)
+
+		mockLogger.EXPECT().AddFuncName("StreamEvents")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(false, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/chat/events", nil)
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.StreamEvents(w, req, api.StreamEventsParams{StreamId: &[]string{streamID}})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("invalid_last_event_id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(nil, nil, nil, nil, nil, nil, nil, sseConfig)
+
+		mockLogger.EXPECT().AddFuncName("StreamEvents")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		req := httptest.NewRequest(http.MethodGet, "/api/chat/events", nil)
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+		handler.StreamEvents(w, req, api.StreamEventsParams{LastEventId: stringPtr("garbage")})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_CentrifugoConnect(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{
+			Centrifuge: config.Centrifuge{ProxyTTL: time.Minute},
+		})
+
+		mockLogger.EXPECT().AddFuncName("CentrifugoConnect")
+		mockRepo.EXPECT().UpdateUserLastOnline(gomock.Any(), userUUID).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/centrifugo/connect", strings.NewReader(`{"client":"c1"}`))
+		req.Header.Set("X-User-ID", userUUID)
+		req = req.WithContext(context.WithValue(req.Context(), config.KeyLogger, mockLogger))
+
+		w := httptest.NewRecorder()
+		handler.CentrifugoConnect(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.CentrifugoConnectResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.NotNil(t, response.Result)
+		assert.Equal(t, userUUID, response.Result.User)
+		assert.Equal(t, []string{model.PersonalChannel(userUUID)}, *response.Result.Channels)
+		require.NotNil(t, response.Result.ExpireAt)
+		assert.Greater(t, *response.Result.ExpireAt, time.Now().Unix())
+	})
+
+	t.Run("missing_user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("CentrifugoConnect")
+		mockLogger.EXPECT().Warn(gomock.Any())
+
+		req := httptest.NewRequest(http.MethodPost, "/centrifugo/connect", strings.NewReader(`{"client":"c1"}`))
+		req = req.WithContext(context.WithValue(req.Context(), config.KeyLogger, mockLogger))
+
+		w := httptest.NewRecorder()
+		handler.CentrifugoConnect(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.CentrifugoConnectResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Nil(t, response.Result)
+		require.NotNil(t, response.Disconnect)
+		assert.Equal(t, uint32(proxyUnauthorizedDisconnect), response.Disconnect.Code)
+	})
+}
+
+func TestHandler_CentrifugoSubscribe(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	tests := []struct {
+		name     string
+		channel  string
+		isMember *bool
+		allowed  bool
+	}{
+		{name: "stream_member", channel: streamID, isMember: boolPtr(true), allowed: true},
+		{name: "revoked_member", channel: streamID, isMember: boolPtr(false), allowed: false},
+		{name: "own_personal_channel", channel: model.PersonalChannel(userUUID), allowed: true},
+		{name: "foreign_personal_channel", channel: model.PersonalChannel(uuid.New().String()), allowed: false},
+		{name: "unknown_channel", channel: "news", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := NewMockDBRepo(ctrl)
+			mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+			handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+			mockLogger.EXPECT().AddFuncName("CentrifugoSubscribe")
+			if !tt.allowed {
+				mockLogger.EXPECT().Warn(gomock.Any())
+			}
+			if tt.isMember != nil {
+				mockRepo.EXPECT().IsStreamMember(gomock.Any(), streamID, userUUID).Return(*tt.isMember, nil)
+			}
+
+			bodyBytes, _ := json.Marshal(api.CentrifugoSubscribeRequest{
+				Client:  "c1",
+				User:    userUUID,
+				Channel: tt.channel,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/centrifugo/subscribe", bytes.NewReader(bodyBytes))
+			req = req.WithContext(context.WithValue(req.Context(), config.KeyLogger, mockLogger))
+
+			w := httptest.NewRecorder()
+			handler.CentrifugoSubscribe(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response api.CentrifugoProxyResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			if tt.allowed {
+				assert.NotNil(t, response.Result)
+				assert.Nil(t, response.Error)
+			} else {
+				assert.Nil(t, response.Result)
+				require.NotNil(t, response.Error)
+				assert.Equal(t, uint32(proxyPermissionDeniedCode), response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_CentrifugoPublish(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	// даже участник, которому разрешено писать, не публикует в канал стрима в обход REST
+	t.Run("stream_channel_denied", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("CentrifugoPublish")
+		mockLogger.EXPECT().Warn(gomock.Any())
+
+		bodyBytes, _ := json.Marshal(api.CentrifugoPublishRequest{
+			Client:  "c1",
+			User:    userUUID,
+			Channel: streamID,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/centrifugo/publish", bytes.NewReader(bodyBytes))
+		req = req.WithContext(context.WithValue(req.Context(), config.KeyLogger, mockLogger))
+
+		w := httptest.NewRecorder()
+		handler.CentrifugoPublish(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.CentrifugoProxyResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		require.NoError(t, err)
+		require.NotNil(t, response.Error)
+		assert.Equal(t, uint32(proxyPermissionDeniedCode), response.Error.Code)
+	})
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestHandler_UploadAttachment(t *testing.T) {
+	t.Parallel()
+
+	uploaderUUID := uuid.New().String()
+	cfg := &config.Config{
+		Storage: config.Storage{
+			MaxUploadSize:    1 << 20,
+			AllowedMimeTypes: []string{"image/*", "application/pdf"},
+			ThumbnailSize:    8,
+		},
+	}
+
+	newUploadRequest := func(t *testing.T, contentType string, data []byte) *http.Request {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+
+		partHeader := textproto.MIMEHeader{}
+		partHeader.Set("Content-Disposition", `form-data; name="file"; filename="upload"`)
+		partHeader.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(partHeader)
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/chat/attachments", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	t.Run("success_image_with_thumbnail", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockBlobStore := NewMockBlobStore(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, mockBlobStore, cfg)
+
+		var img bytes.Buffer
+		require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 32, 16))))
+
+		mockLogger.EXPECT().AddFuncName("UploadAttachment")
+		gomock.InOrder(
+			mockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(img.Len()), "image/png").Return(nil),
+			mockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "image/jpeg").Return(nil),
+		)
+		mockRepo.EXPECT().SaveAttachment(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, attachment *model.Attachment) error {
+			assert.Equal(t, uploaderUUID, attachment.UploaderID.String())
+			require.NotNil(t, attachment.ThumbnailKey)
+			return nil
+		})
+
+		req := newUploadRequest(t, "image/png", img.Bytes())
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, uploaderUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+
+		handler.UploadAttachment(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.Attachment
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "image/png", response.MimeType)
+		assert.Equal(t, int64(img.Len()), response.Size)
+		require.NotNil(t, response.Width)
+		require.NotNil(t, response.Height)
+		assert.Equal(t, int32(32), *response.Width)
+		assert.Equal(t, int32(16), *response.Height)
+		require.NotNil(t, response.ThumbnailKey)
+		assert.Equal(t, response.StorageKey+"_thumb", *response.ThumbnailKey)
+	})
+
+	t.Run("thumbnail_failed_removes_original", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockBlobStore := NewMockBlobStore(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(nil, nil, nil, nil, nil, nil, mockBlobStore, cfg)
+
+		var img bytes.Buffer
+		require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 32, 16))))
+
+		var originalKey string
+		mockLogger.EXPECT().AddFuncName("UploadAttachment")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(img.Len()), "image/png").
+			DoAndReturn(func(_ context.Context, key string, _ io.Reader, _ int64, _ string) error {
+				originalKey = key
+				return nil
+			})
+		mockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "image/jpeg").Return(errors.New("storage unavailable"))
+		mockBlobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key string) error {
+			assert.Equal(t, originalKey, key)
+			return nil
+		})
+
+		req := newUploadRequest(t, "image/png", img.Bytes())
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, uploaderUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+
+		handler.UploadAttachment(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("save_failed_removes_objects", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockBlobStore := NewMockBlobStore(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, mockBlobStore, cfg)
+
+		var stored []string
+		mockLogger.EXPECT().AddFuncName("UploadAttachment")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+			DoAndReturn(func(_ context.Context, key string, _ io.Reader, _ int64, _ string) error {
+				stored = append(stored, key)
+				return nil
+			})
+		mockRepo.EXPECT().SaveAttachment(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+		mockBlobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(_ context.Context, key string) error {
+			assert.Contains(t, stored, key)
+			return nil
+		})
+
+		var img bytes.Buffer
+		require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 32, 16))))
+
+		req := newUploadRequest(t, "image/png", img.Bytes())
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, uploaderUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+
+		handler.UploadAttachment(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("invalid_uploader_id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(nil, nil, nil, nil, nil, nil, nil, cfg)
+
+		mockLogger.EXPECT().AddFuncName("UploadAttachment")
+
+		req := newUploadRequest(t, "application/pdf", []byte("%PDF-1.4"))
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, "not-a-uuid")
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+
+		handler.UploadAttachment(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("mime_not_allowed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(nil, nil, nil, nil, nil, nil, nil, cfg)
+
+		mockLogger.EXPECT().AddFuncName("UploadAttachment")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		req := newUploadRequest(t, "application/x-msdownload", []byte("MZ"))
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, uploaderUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+
+		handler.UploadAttachment(w, req)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("too_large", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(nil, nil, nil, nil, nil, nil, nil, cfg)
+
+		mockLogger.EXPECT().AddFuncName("UploadAttachment")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		req := newUploadRequest(t, "application/pdf", make([]byte, cfg.Storage.MaxUploadSize+1))
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, uploaderUUID)
+		req = req.WithContext(reqCtx)
+
+		w := httptest.NewRecorder()
+
+		handler.UploadAttachment(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+func TestHandler_CompleteAttachment(t *testing.T) {
+	t.Parallel()
+
+	uploaderUUID := uuid.New().String()
+	storageKey := "attachments/" + uploaderUUID + "/" + uuid.New().String()
+	cfg := &config.Config{
+		Storage: config.Storage{
+			MaxUploadSize:    1 << 20,
+			AllowedMimeTypes: []string{"image/*", "application/pdf"},
+			ThumbnailSize:    8,
+		},
+	}
+
+	newRequest := func(mockLogger *logger_lib.MockLoggerInterface, key string) *http.Request {
+		body := fmt.Sprintf(`{"storage_key":%q}`, key)
+		req := httptest.NewRequest(http.MethodPost, "/api/chat/attachments/complete", bytes.NewBufferString(body))
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, uploaderUUID)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("success_image_with_thumbnail", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockBlobStore := NewMockBlobStore(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, mockBlobStore, cfg)
+
+		var img bytes.Buffer
+		require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 32, 16))))
+
+		mockLogger.EXPECT().AddFuncName("CompleteAttachment")
+		mockRepo.EXPECT().GetAttachment(gomock.Any(), storageKey).Return(nil, nil)
+		mockBlobStore.EXPECT().Get(gomock.Any(), storageKey).Return(io.NopCloser(bytes.NewReader(img.Bytes())), nil)
+		mockBlobStore.EXPECT().Put(gomock.Any(), storageKey+"_thumb", gomock.Any(), gomock.Any(), "image/jpeg").Return(nil)
+		mockRepo.EXPECT().SaveAttachment(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, attachment *model.Attachment) error {
+			assert.Equal(t, uploaderUUID, attachment.UploaderID.String())
+			assert.Equal(t, "image/png", attachment.MimeType)
+			assert.Equal(t, int64(img.Len()), attachment.Size)
+			return nil
+		})
+
+		w := httptest.NewRecorder()
+		handler.CompleteAttachment(w, newRequest(mockLogger, storageKey))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.Attachment
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, storageKey, response.StorageKey)
+		require.NotNil(t, response.ThumbnailKey)
+		assert.Equal(t, storageKey+"_thumb", *response.ThumbnailKey)
+	})
+
+	t.Run("already_completed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, cfg)
+
+		mockLogger.EXPECT().AddFuncName("CompleteAttachment")
+		mockRepo.EXPECT().GetAttachment(gomock.Any(), storageKey).Return(&model.Attachment{
+			StorageKey: storageKey,
+			UploaderID: uuid.MustParse(uploaderUUID),
+			MimeType:   "application/pdf",
+			Size:       42,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		handler.CompleteAttachment(w, newRequest(mockLogger, storageKey))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not_uploaded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockBlobStore := NewMockBlobStore(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, mockBlobStore, cfg)
+
+		mockLogger.EXPECT().AddFuncName("CompleteAttachment")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().GetAttachment(gomock.Any(), storageKey).Return(nil, nil)
+		mockBlobStore.EXPECT().Get(gomock.Any(), storageKey).Return(nil, blobstore.ErrObjectNotFound)
+
+		w := httptest.NewRecorder()
+		handler.CompleteAttachment(w, newRequest(mockLogger, storageKey))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("sniffed_mime_not_allowed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockBlobStore := NewMockBlobStore(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(mockRepo, nil, nil, nil, nil, nil, mockBlobStore, cfg)
+
+		mockLogger.EXPECT().AddFuncName("CompleteAttachment")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().GetAttachment(gomock.Any(), storageKey).Return(nil, nil)
+		mockBlobStore.EXPECT().Get(gomock.Any(), storageKey).Return(io.NopCloser(bytes.NewReader([]byte("MZ\x90\x00"))), nil)
+		mockBlobStore.EXPECT().Delete(gomock.Any(), storageKey).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.CompleteAttachment(w, newRequest(mockLogger, storageKey))
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("invalid_uploader_id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(nil, nil, nil, nil, nil, nil, nil, cfg)
+
+		mockLogger.EXPECT().AddFuncName("CompleteAttachment")
+
+		req := newRequest(mockLogger, storageKey)
+		req = req.WithContext(context.WithValue(req.Context(), config.KeyUUID, "not-a-uuid"))
+
+		w := httptest.NewRecorder()
+		handler.CompleteAttachment(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("foreign_storage_key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		handler := New(nil, nil, nil, nil, nil, nil, nil, cfg)
+
+		mockLogger.EXPECT().AddFuncName("CompleteAttachment")
+
+		w := httptest.NewRecorder()
+		handler.CompleteAttachment(w, newRequest(mockLogger, "attachments/"+uuid.New().String()+"/"+uuid.New().String()))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_GetPrivateStreamWith(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	companionUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	newRequest := func(mockLogger *logger_lib.MockLoggerInterface) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/chat/streams/private/with/"+companionUUID, nil)
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetPrivateStreamWith")
+		mockRepo.EXPECT().GetPrivateStreamByPair(gomock.Any(), model.PrivatePair(uuid.MustParse(userUUID), uuid.MustParse(companionUUID))).Return(streamID, nil)
+
+		w := httptest.NewRecorder()
+		handler.GetPrivateStreamWith(w, newRequest(mockLogger), companionUUID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.GetPrivateStreamWithResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, streamID, response.StreamId)
+	})
+
+	t.Run("non_canonical_user_id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetPrivateStreamWith")
+		mockRepo.EXPECT().GetPrivateStreamByPair(gomock.Any(), model.PrivatePair(uuid.MustParse(userUUID), uuid.MustParse(companionUUID))).Return(streamID, nil)
+
+		w := httptest.NewRecorder()
+		handler.GetPrivateStreamWith(w, newRequest(mockLogger), "{"+strings.ToUpper(companionUUID)+"}")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetPrivateStreamWith")
+		mockRepo.EXPECT().GetPrivateStreamByPair(gomock.Any(), gomock.Any()).Return("", nil)
+
+		w := httptest.NewRecorder()
+		handler.GetPrivateStreamWith(w, newRequest(mockLogger), companionUUID)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid_requester_id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetPrivateStreamWith")
+
+		req := newRequest(mockLogger)
+		req = req.WithContext(context.WithValue(req.Context(), config.KeyUUID, "not-a-uuid"))
+
+		w := httptest.NewRecorder()
+		handler.GetPrivateStreamWith(w, req, companionUUID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid_user_id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("GetPrivateStreamWith")
+
+		w := httptest.NewRecorder()
+		handler.GetPrivateStreamWith(w, newRequest(mockLogger), "not-a-uuid")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_StreamNotifications(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	streamID := uuid.New().String()
+
+	newRequest := func(method, body string, mockLogger *logger_lib.MockLoggerInterface) *http.Request {
+		req := httptest.NewRequest(method, "/api/chat/streams/"+streamID+"/mute", bytes.NewBufferString(body))
+		reqCtx := context.WithValue(req.Context(), config.KeyLogger, mockLogger)
+		reqCtx = context.WithValue(reqCtx, config.KeyUUID, userUUID)
+		return req.WithContext(reqCtx)
+	}
+
+	t.Run("update_level", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("UpdateStreamNotifications")
+		mockRepo.EXPECT().UpdateNotifyLevel(gomock.Any(), streamID, userUUID, model.MentionsNotifyLevel).
+			Return(&model.NotificationSettings{Notify: true, Level: model.MentionsNotifyLevel}, nil)
+
+		w := httptest.NewRecorder()
+		handler.UpdateStreamNotifications(w, newRequest(http.MethodPut, `{"level":"mentions"}`, mockLogger), streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.StreamNotificationsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, api.NotifyMentions, response.Level)
+		assert.False(t, response.Muted)
+	})
+
+	t.Run("update_level_unsupported", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("UpdateStreamNotifications")
+
+		w := httptest.NewRecorder()
+		handler.UpdateStreamNotifications(w, newRequest(http.MethodPut, `{"level":"loud"}`, mockLogger), streamID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("update_level_not_member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("UpdateStreamNotifications")
+		mockRepo.EXPECT().UpdateNotifyLevel(gomock.Any(), streamID, userUUID, model.NoneNotifyLevel).Return(nil, nil)
+
+		w := httptest.NewRecorder()
+		handler.UpdateStreamNotifications(w, newRequest(http.MethodPut, `{"level":"none"}`, mockLogger), streamID)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("mute_forever_without_body", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("MuteStream")
+		mockRepo.EXPECT().UpdateStreamMute(gomock.Any(), streamID, userUUID, true, nil).
+			Return(&model.NotificationSettings{Notify: false, Level: model.AllNotifyLevel}, nil)
+
+		w := httptest.NewRecorder()
+		handler.MuteStream(w, newRequest(http.MethodPost, "", mockLogger), streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.StreamNotificationsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Muted)
+		assert.Nil(t, response.MutedUntil)
+	})
+
+	t.Run("mute_until", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+		mockLogger.EXPECT().AddFuncName("MuteStream")
+		mockRepo.EXPECT().UpdateStreamMute(gomock.Any(), streamID, userUUID, true, &until).
+			Return(&model.NotificationSettings{Notify: false, Level: model.AllNotifyLevel, MutedUntil: &until}, nil)
+
+		w := httptest.NewRecorder()
+		body := `{"until":"` + until.Format(time.RFC3339) + `"}`
+		handler.MuteStream(w, newRequest(http.MethodPost, body, mockLogger), streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.StreamNotificationsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Muted)
+		require.NotNil(t, response.MutedUntil)
+		assert.Equal(t, until.Format(time.RFC3339), *response.MutedUntil)
+	})
+
+	t.Run("mute_until_in_past", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("MuteStream")
+
+		w := httptest.NewRecorder()
+		body := `{"until":"` + time.Now().Add(-time.Hour).Format(time.RFC3339) + `"}`
+		handler.MuteStream(w, newRequest(http.MethodPost, body, mockLogger), streamID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unmute", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+		handler := New(mockRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+		mockLogger.EXPECT().AddFuncName("UnmuteStream")
+		mockRepo.EXPECT().UpdateStreamMute(gomock.Any(), streamID, userUUID, false, nil).
+			Return(&model.NotificationSettings{Notify: true, Level: model.NoneNotifyLevel}, nil)
+
+		w := httptest.NewRecorder()
+		handler.UnmuteStream(w, newRequest(http.MethodDelete, "", mockLogger), streamID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response api.StreamNotificationsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.False(t, response.Muted)
+		assert.Equal(t, api.NotifyNone, response.Level)
+	})
 }
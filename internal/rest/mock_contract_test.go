@@ -1,12 +1,14 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: handler.go
+// Source: contract.go
 
 // Package rest is a generated GoMock package.
 package rest
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	api "github.com/s21platform/chat-service/internal/generated"
@@ -93,6 +95,156 @@ func (mr *MockDBRepoMockRecorder) CreateStream(ctx, streamType, metadata, create
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStream", reflect.TypeOf((*MockDBRepo)(nil).CreateStream), ctx, streamType, metadata, createdBy)
 }
 
+// DeleteMessage mocks base method.
+func (m *MockDBRepo) DeleteMessage(ctx context.Context, messageID, deletedBy string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, messageID, deletedBy)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockDBRepoMockRecorder) DeleteMessage(ctx, messageID, deletedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockDBRepo)(nil).DeleteMessage), ctx, messageID, deletedBy)
+}
+
+// EnqueueOutbox mocks base method.
+func (m *MockDBRepo) EnqueueOutbox(ctx context.Context, channel string, data interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueOutbox", ctx, channel, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueOutbox indicates an expected call of EnqueueOutbox.
+func (mr *MockDBRepoMockRecorder) EnqueueOutbox(ctx, channel, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueOutbox", reflect.TypeOf((*MockDBRepo)(nil).EnqueueOutbox), ctx, channel, data)
+}
+
+// GetAttachment mocks base method.
+func (m *MockDBRepo) GetAttachment(ctx context.Context, storageKey string) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, storageKey)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockDBRepoMockRecorder) GetAttachment(ctx, storageKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockDBRepo)(nil).GetAttachment), ctx, storageKey)
+}
+
+// GetChannelStreams mocks base method.
+func (m *MockDBRepo) GetChannelStreams(ctx context.Context, requesterID string) (*model.ChannelStreamPreviewList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelStreams", ctx, requesterID)
+	ret0, _ := ret[0].(*model.ChannelStreamPreviewList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChannelStreams indicates an expected call of GetChannelStreams.
+func (mr *MockDBRepoMockRecorder) GetChannelStreams(ctx, requesterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelStreams", reflect.TypeOf((*MockDBRepo)(nil).GetChannelStreams), ctx, requesterID)
+}
+
+// GetEventsAfter mocks base method.
+func (m *MockDBRepo) GetEventsAfter(ctx context.Context, userID string, streamIDs []string, after model.EventCursor, limit int) ([]model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsAfter", ctx, userID, streamIDs, after, limit)
+	ret0, _ := ret[0].([]model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsAfter indicates an expected call of GetEventsAfter.
+func (mr *MockDBRepoMockRecorder) GetEventsAfter(ctx, userID, streamIDs, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsAfter", reflect.TypeOf((*MockDBRepo)(nil).GetEventsAfter), ctx, userID, streamIDs, after, limit)
+}
+
+// GetGroupStreams mocks base method.
+func (m *MockDBRepo) GetGroupStreams(ctx context.Context, requesterID string) (*model.GroupStreamPreviewList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupStreams", ctx, requesterID)
+	ret0, _ := ret[0].(*model.GroupStreamPreviewList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupStreams indicates an expected call of GetGroupStreams.
+func (mr *MockDBRepoMockRecorder) GetGroupStreams(ctx, requesterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupStreams", reflect.TypeOf((*MockDBRepo)(nil).GetGroupStreams), ctx, requesterID)
+}
+
+// GetLastEventCursor mocks base method.
+func (m *MockDBRepo) GetLastEventCursor(ctx context.Context) (model.EventCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastEventCursor", ctx)
+	ret0, _ := ret[0].(model.EventCursor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastEventCursor indicates an expected call of GetLastEventCursor.
+func (mr *MockDBRepoMockRecorder) GetLastEventCursor(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastEventCursor", reflect.TypeOf((*MockDBRepo)(nil).GetLastEventCursor), ctx)
+}
+
+// GetMessage mocks base method.
+func (m *MockDBRepo) GetMessage(ctx context.Context, messageID string) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessage", ctx, messageID)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessage indicates an expected call of GetMessage.
+func (mr *MockDBRepoMockRecorder) GetMessage(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessage", reflect.TypeOf((*MockDBRepo)(nil).GetMessage), ctx, messageID)
+}
+
+// GetOrCreatePrivateStream mocks base method.
+func (m *MockDBRepo) GetOrCreatePrivateStream(ctx context.Context, metadata, createdBy, pair string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreatePrivateStream", ctx, metadata, createdBy, pair)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOrCreatePrivateStream indicates an expected call of GetOrCreatePrivateStream.
+func (mr *MockDBRepoMockRecorder) GetOrCreatePrivateStream(ctx, metadata, createdBy, pair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreatePrivateStream", reflect.TypeOf((*MockDBRepo)(nil).GetOrCreatePrivateStream), ctx, metadata, createdBy, pair)
+}
+
+// GetPrivateStreamByPair mocks base method.
+func (m *MockDBRepo) GetPrivateStreamByPair(ctx context.Context, pair string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateStreamByPair", ctx, pair)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrivateStreamByPair indicates an expected call of GetPrivateStreamByPair.
+func (mr *MockDBRepoMockRecorder) GetPrivateStreamByPair(ctx, pair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateStreamByPair", reflect.TypeOf((*MockDBRepo)(nil).GetPrivateStreamByPair), ctx, pair)
+}
+
 // GetPrivateStreams mocks base method.
 func (m *MockDBRepo) GetPrivateStreams(ctx context.Context, requesterID string) (*model.PrivateStreamPreviewList, error) {
 	m.ctrl.T.Helper()
@@ -108,19 +260,124 @@ func (mr *MockDBRepoMockRecorder) GetPrivateStreams(ctx, requesterID interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateStreams", reflect.TypeOf((*MockDBRepo)(nil).GetPrivateStreams), ctx, requesterID)
 }
 
+// GetStreamMembership mocks base method.
+func (m *MockDBRepo) GetStreamMembership(ctx context.Context, streamID, userID string) (*model.StreamMembership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamMembership", ctx, streamID, userID)
+	ret0, _ := ret[0].(*model.StreamMembership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamMembership indicates an expected call of GetStreamMembership.
+func (mr *MockDBRepoMockRecorder) GetStreamMembership(ctx, streamID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamMembership", reflect.TypeOf((*MockDBRepo)(nil).GetStreamMembership), ctx, streamID, userID)
+}
+
+// GetStreamMessage mocks base method.
+func (m *MockDBRepo) GetStreamMessage(ctx context.Context, streamID, userID, messageID string) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamMessage", ctx, streamID, userID, messageID)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamMessage indicates an expected call of GetStreamMessage.
+func (mr *MockDBRepoMockRecorder) GetStreamMessage(ctx, streamID, userID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamMessage", reflect.TypeOf((*MockDBRepo)(nil).GetStreamMessage), ctx, streamID, userID, messageID)
+}
+
+// GetStreamPersonalChannels mocks base method.
+func (m *MockDBRepo) GetStreamPersonalChannels(ctx context.Context, streamID string, userIDs []string) ([]model.PersonalChannelMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamPersonalChannels", ctx, streamID, userIDs)
+	ret0, _ := ret[0].([]model.PersonalChannelMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamPersonalChannels indicates an expected call of GetStreamPersonalChannels.
+func (mr *MockDBRepoMockRecorder) GetStreamPersonalChannels(ctx, streamID, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamPersonalChannels", reflect.TypeOf((*MockDBRepo)(nil).GetStreamPersonalChannels), ctx, streamID, userIDs)
+}
+
+// GetStreamReadMarks mocks base method.
+func (m *MockDBRepo) GetStreamReadMarks(ctx context.Context, streamID string, since model.MessageCursor) (model.ReadMarkList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamReadMarks", ctx, streamID, since)
+	ret0, _ := ret[0].(model.ReadMarkList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamReadMarks indicates an expected call of GetStreamReadMarks.
+func (mr *MockDBRepoMockRecorder) GetStreamReadMarks(ctx, streamID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamReadMarks", reflect.TypeOf((*MockDBRepo)(nil).GetStreamReadMarks), ctx, streamID, since)
+}
+
 // GetStreamRecentMessages mocks base method.
-func (m *MockDBRepo) GetStreamRecentMessages(ctx context.Context, streamID, offset string, limit int32) (*model.MessageList, error) {
+func (m *MockDBRepo) GetStreamRecentMessages(ctx context.Context, streamID, userID string, cursor *model.MessageCursor, direction string, limit int32) (*model.MessageList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStreamRecentMessages", ctx, streamID, offset, limit)
+	ret := m.ctrl.Call(m, "GetStreamRecentMessages", ctx, streamID, userID, cursor, direction, limit)
 	ret0, _ := ret[0].(*model.MessageList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetStreamRecentMessages indicates an expected call of GetStreamRecentMessages.
-func (mr *MockDBRepoMockRecorder) GetStreamRecentMessages(ctx, streamID, offset, limit interface{}) *gomock.Call {
+func (mr *MockDBRepoMockRecorder) GetStreamRecentMessages(ctx, streamID, userID, cursor, direction, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamRecentMessages", reflect.TypeOf((*MockDBRepo)(nil).GetStreamRecentMessages), ctx, streamID, userID, cursor, direction, limit)
+}
+
+// GetStreamType mocks base method.
+func (m *MockDBRepo) GetStreamType(ctx context.Context, streamID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamType", ctx, streamID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamType indicates an expected call of GetStreamType.
+func (mr *MockDBRepoMockRecorder) GetStreamType(ctx, streamID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamRecentMessages", reflect.TypeOf((*MockDBRepo)(nil).GetStreamRecentMessages), ctx, streamID, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamType", reflect.TypeOf((*MockDBRepo)(nil).GetStreamType), ctx, streamID)
+}
+
+// GetStreams mocks base method.
+func (m *MockDBRepo) GetStreams(ctx context.Context, requesterID, streamType string, cursor *model.StreamCursor, limit int) (*model.StreamPreviewList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreams", ctx, requesterID, streamType, cursor, limit)
+	ret0, _ := ret[0].(*model.StreamPreviewList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreams indicates an expected call of GetStreams.
+func (mr *MockDBRepoMockRecorder) GetStreams(ctx, requesterID, streamType, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreams", reflect.TypeOf((*MockDBRepo)(nil).GetStreams), ctx, requesterID, streamType, cursor, limit)
+}
+
+// GetThreadMessages mocks base method.
+func (m *MockDBRepo) GetThreadMessages(ctx context.Context, streamID, rootID, userID string, cursor *model.MessageCursor, limit int32) (*model.MessageList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThreadMessages", ctx, streamID, rootID, userID, cursor, limit)
+	ret0, _ := ret[0].(*model.MessageList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThreadMessages indicates an expected call of GetThreadMessages.
+func (mr *MockDBRepoMockRecorder) GetThreadMessages(ctx, streamID, rootID, userID, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThreadMessages", reflect.TypeOf((*MockDBRepo)(nil).GetThreadMessages), ctx, streamID, rootID, userID, cursor, limit)
 }
 
 // GetUserActiveStreams mocks base method.
@@ -138,6 +395,35 @@ func (mr *MockDBRepoMockRecorder) GetUserActiveStreams(ctx, userID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserActiveStreams", reflect.TypeOf((*MockDBRepo)(nil).GetUserActiveStreams), ctx, userID)
 }
 
+// GetUserSnapshot mocks base method.
+func (m *MockDBRepo) GetUserSnapshot(ctx context.Context, userID string) (*model.UserSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSnapshot", ctx, userID)
+	ret0, _ := ret[0].(*model.UserSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSnapshot indicates an expected call of GetUserSnapshot.
+func (mr *MockDBRepoMockRecorder) GetUserSnapshot(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSnapshot", reflect.TypeOf((*MockDBRepo)(nil).GetUserSnapshot), ctx, userID)
+}
+
+// HideMessage mocks base method.
+func (m *MockDBRepo) HideMessage(ctx context.Context, messageID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideMessage", ctx, messageID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HideMessage indicates an expected call of HideMessage.
+func (mr *MockDBRepoMockRecorder) HideMessage(ctx, messageID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideMessage", reflect.TypeOf((*MockDBRepo)(nil).HideMessage), ctx, messageID, userID)
+}
+
 // IsStreamMember mocks base method.
 func (m *MockDBRepo) IsStreamMember(ctx context.Context, streamID, userID string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -153,6 +439,64 @@ func (mr *MockDBRepoMockRecorder) IsStreamMember(ctx, streamID, userID interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsStreamMember", reflect.TypeOf((*MockDBRepo)(nil).IsStreamMember), ctx, streamID, userID)
 }
 
+// LeaveStream mocks base method.
+func (m *MockDBRepo) LeaveStream(ctx context.Context, streamID, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveStream", ctx, streamID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeaveStream indicates an expected call of LeaveStream.
+func (mr *MockDBRepoMockRecorder) LeaveStream(ctx, streamID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveStream", reflect.TypeOf((*MockDBRepo)(nil).LeaveStream), ctx, streamID, userID)
+}
+
+// MarkStreamRead mocks base method.
+func (m *MockDBRepo) MarkStreamRead(ctx context.Context, streamID, userID, messageID string) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkStreamRead", ctx, streamID, userID, messageID)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkStreamRead indicates an expected call of MarkStreamRead.
+func (mr *MockDBRepoMockRecorder) MarkStreamRead(ctx, streamID, userID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkStreamRead", reflect.TypeOf((*MockDBRepo)(nil).MarkStreamRead), ctx, streamID, userID, messageID)
+}
+
+// RemoveUserSubscription mocks base method.
+func (m *MockDBRepo) RemoveUserSubscription(ctx context.Context, userID, channel string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUserSubscription", ctx, userID, channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveUserSubscription indicates an expected call of RemoveUserSubscription.
+func (mr *MockDBRepoMockRecorder) RemoveUserSubscription(ctx, userID, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserSubscription", reflect.TypeOf((*MockDBRepo)(nil).RemoveUserSubscription), ctx, userID, channel)
+}
+
+// SaveAttachment mocks base method.
+func (m *MockDBRepo) SaveAttachment(ctx context.Context, attachment *model.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttachment", ctx, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttachment indicates an expected call of SaveAttachment.
+func (mr *MockDBRepoMockRecorder) SaveAttachment(ctx, attachment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttachment", reflect.TypeOf((*MockDBRepo)(nil).SaveAttachment), ctx, attachment)
+}
+
 // SaveMessage mocks base method.
 func (m *MockDBRepo) SaveMessage(ctx context.Context, message *model.Message) error {
 	m.ctrl.T.Helper()
@@ -167,6 +511,94 @@ func (mr *MockDBRepoMockRecorder) SaveMessage(ctx, message interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMessage", reflect.TypeOf((*MockDBRepo)(nil).SaveMessage), ctx, message)
 }
 
+// UpdateMemberRole mocks base method.
+func (m *MockDBRepo) UpdateMemberRole(ctx context.Context, streamID, userID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, streamID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockDBRepoMockRecorder) UpdateMemberRole(ctx, streamID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockDBRepo)(nil).UpdateMemberRole), ctx, streamID, userID, role)
+}
+
+// UpdateMessageContent mocks base method.
+func (m *MockDBRepo) UpdateMessageContent(ctx context.Context, messageID, content string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessageContent", ctx, messageID, content)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMessageContent indicates an expected call of UpdateMessageContent.
+func (mr *MockDBRepoMockRecorder) UpdateMessageContent(ctx, messageID, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessageContent", reflect.TypeOf((*MockDBRepo)(nil).UpdateMessageContent), ctx, messageID, content)
+}
+
+// UpdateNotifyLevel mocks base method.
+func (m *MockDBRepo) UpdateNotifyLevel(ctx context.Context, streamID, userID, level string) (*model.NotificationSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotifyLevel", ctx, streamID, userID, level)
+	ret0, _ := ret[0].(*model.NotificationSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNotifyLevel indicates an expected call of UpdateNotifyLevel.
+func (mr *MockDBRepoMockRecorder) UpdateNotifyLevel(ctx, streamID, userID, level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotifyLevel", reflect.TypeOf((*MockDBRepo)(nil).UpdateNotifyLevel), ctx, streamID, userID, level)
+}
+
+// UpdateStreamMetadata mocks base method.
+func (m *MockDBRepo) UpdateStreamMetadata(ctx context.Context, streamID, patch string) (*model.StreamMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStreamMetadata", ctx, streamID, patch)
+	ret0, _ := ret[0].(*model.StreamMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStreamMetadata indicates an expected call of UpdateStreamMetadata.
+func (mr *MockDBRepoMockRecorder) UpdateStreamMetadata(ctx, streamID, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStreamMetadata", reflect.TypeOf((*MockDBRepo)(nil).UpdateStreamMetadata), ctx, streamID, patch)
+}
+
+// UpdateStreamMute mocks base method.
+func (m *MockDBRepo) UpdateStreamMute(ctx context.Context, streamID, userID string, muted bool, until *time.Time) (*model.NotificationSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStreamMute", ctx, streamID, userID, muted, until)
+	ret0, _ := ret[0].(*model.NotificationSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStreamMute indicates an expected call of UpdateStreamMute.
+func (mr *MockDBRepoMockRecorder) UpdateStreamMute(ctx, streamID, userID, muted, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStreamMute", reflect.TypeOf((*MockDBRepo)(nil).UpdateStreamMute), ctx, streamID, userID, muted, until)
+}
+
+// UpdateUserLastOnline mocks base method.
+func (m *MockDBRepo) UpdateUserLastOnline(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserLastOnline", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserLastOnline indicates an expected call of UpdateUserLastOnline.
+func (mr *MockDBRepoMockRecorder) UpdateUserLastOnline(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserLastOnline", reflect.TypeOf((*MockDBRepo)(nil).UpdateUserLastOnline), ctx, userID)
+}
+
 // WithTx mocks base method.
 func (m *MockDBRepo) WithTx(ctx context.Context, cb func(context.Context) error) error {
 	m.ctrl.T.Helper()
@@ -242,8 +674,22 @@ func (m *MockCetrifugeClient) EXPECT() *MockCetrifugeClientMockRecorder {
 	return m.recorder
 }
 
+// Broadcast mocks base method.
+func (m *MockCetrifugeClient) Broadcast(ctx context.Context, channels []string, data interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Broadcast", ctx, channels, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Broadcast indicates an expected call of Broadcast.
+func (mr *MockCetrifugeClientMockRecorder) Broadcast(ctx, channels, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Broadcast", reflect.TypeOf((*MockCetrifugeClient)(nil).Broadcast), ctx, channels, data)
+}
+
 // Publish mocks base method.
-func (m *MockCetrifugeClient) Publish(ctx context.Context, channel string, data model.Message) error {
+func (m *MockCetrifugeClient) Publish(ctx context.Context, channel string, data interface{}) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Publish", ctx, channel, data)
 	ret0, _ := ret[0].(error)
@@ -256,6 +702,87 @@ func (mr *MockCetrifugeClientMockRecorder) Publish(ctx, channel, data interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockCetrifugeClient)(nil).Publish), ctx, channel, data)
 }
 
+// PublishBatch mocks base method.
+func (m *MockCetrifugeClient) PublishBatch(ctx context.Context, publications []model.ChannelPublication) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishBatch", ctx, publications)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishBatch indicates an expected call of PublishBatch.
+func (mr *MockCetrifugeClientMockRecorder) PublishBatch(ctx, publications interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishBatch", reflect.TypeOf((*MockCetrifugeClient)(nil).PublishBatch), ctx, publications)
+}
+
+// MockPresenceClient is a mock of PresenceClient interface.
+type MockPresenceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresenceClientMockRecorder
+}
+
+// MockPresenceClientMockRecorder is the mock recorder for MockPresenceClient.
+type MockPresenceClientMockRecorder struct {
+	mock *MockPresenceClient
+}
+
+// NewMockPresenceClient creates a new mock instance.
+func NewMockPresenceClient(ctrl *gomock.Controller) *MockPresenceClient {
+	mock := &MockPresenceClient{ctrl: ctrl}
+	mock.recorder = &MockPresenceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPresenceClient) EXPECT() *MockPresenceClientMockRecorder {
+	return m.recorder
+}
+
+// Presence mocks base method.
+func (m *MockPresenceClient) Presence(ctx context.Context, channel string) (map[string]model.CentrifugoClientInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Presence", ctx, channel)
+	ret0, _ := ret[0].(map[string]model.CentrifugoClientInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Presence indicates an expected call of Presence.
+func (mr *MockPresenceClientMockRecorder) Presence(ctx, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Presence", reflect.TypeOf((*MockPresenceClient)(nil).Presence), ctx, channel)
+}
+
+// PresenceStatsBatch mocks base method.
+func (m *MockPresenceClient) PresenceStatsBatch(ctx context.Context, channels []string) ([]*model.PresenceStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresenceStatsBatch", ctx, channels)
+	ret0, _ := ret[0].([]*model.PresenceStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresenceStatsBatch indicates an expected call of PresenceStatsBatch.
+func (mr *MockPresenceClientMockRecorder) PresenceStatsBatch(ctx, channels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresenceStatsBatch", reflect.TypeOf((*MockPresenceClient)(nil).PresenceStatsBatch), ctx, channels)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockPresenceClient) Unsubscribe(ctx context.Context, user, channel string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, user, channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockPresenceClientMockRecorder) Unsubscribe(ctx, user, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockPresenceClient)(nil).Unsubscribe), ctx, user, channel)
+}
+
 // MockValidator is a mock of Validator interface.
 type MockValidator struct {
 	ctrl     *gomock.Controller
@@ -293,6 +820,20 @@ func (mr *MockValidatorMockRecorder) ValidateCreateStream(req, creatorID interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateCreateStream", reflect.TypeOf((*MockValidator)(nil).ValidateCreateStream), req, creatorID)
 }
 
+// ValidateEditMessage mocks base method.
+func (m *MockValidator) ValidateEditMessage(req *api.EditMessageRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateEditMessage", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateEditMessage indicates an expected call of ValidateEditMessage.
+func (mr *MockValidatorMockRecorder) ValidateEditMessage(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateEditMessage", reflect.TypeOf((*MockValidator)(nil).ValidateEditMessage), req)
+}
+
 // ValidateSendMessage mocks base method.
 func (m *MockValidator) ValidateSendMessage(req *api.SendMessageRequest) error {
 	m.ctrl.T.Helper()
@@ -307,6 +848,101 @@ func (mr *MockValidatorMockRecorder) ValidateSendMessage(req interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateSendMessage", reflect.TypeOf((*MockValidator)(nil).ValidateSendMessage), req)
 }
 
+// ValidateUpdateStream mocks base method.
+func (m *MockValidator) ValidateUpdateStream(req *api.UpdateStreamRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateUpdateStream", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateUpdateStream indicates an expected call of ValidateUpdateStream.
+func (mr *MockValidatorMockRecorder) ValidateUpdateStream(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateUpdateStream", reflect.TypeOf((*MockValidator)(nil).ValidateUpdateStream), req)
+}
+
+// MockBlobStore is a mock of BlobStore interface.
+type MockBlobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobStoreMockRecorder
+}
+
+// MockBlobStoreMockRecorder is the mock recorder for MockBlobStore.
+type MockBlobStoreMockRecorder struct {
+	mock *MockBlobStore
+}
+
+// NewMockBlobStore creates a new mock instance.
+func NewMockBlobStore(ctrl *gomock.Controller) *MockBlobStore {
+	mock := &MockBlobStore{ctrl: ctrl}
+	mock.recorder = &MockBlobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobStore) EXPECT() *MockBlobStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobStoreMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlobStore)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBlobStoreMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBlobStore)(nil).Get), ctx, key)
+}
+
+// PresignPut mocks base method.
+func (m *MockBlobStore) PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignPut", ctx, key, contentType, size, expires)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignPut indicates an expected call of PresignPut.
+func (mr *MockBlobStoreMockRecorder) PresignPut(ctx, key, contentType, size, expires interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignPut", reflect.TypeOf((*MockBlobStore)(nil).PresignPut), ctx, key, contentType, size, expires)
+}
+
+// Put mocks base method.
+func (m *MockBlobStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, body, size, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockBlobStoreMockRecorder) Put(ctx, key, body, size, contentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBlobStore)(nil).Put), ctx, key, body, size, contentType)
+}
+
 // MockJWTGenerator is a mock of JWTGenerator interface.
 type MockJWTGenerator struct {
 	ctrl     *gomock.Controller
@@ -331,9 +967,9 @@ func (m *MockJWTGenerator) EXPECT() *MockJWTGeneratorMockRecorder {
 }
 
 // GenerateConnectToken mocks base method.
-func (m *MockJWTGenerator) GenerateConnectToken(userID string) (string, int64, error) {
+func (m *MockJWTGenerator) GenerateConnectToken(userID string, channels []string) (string, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateConnectToken", userID)
+	ret := m.ctrl.Call(m, "GenerateConnectToken", userID, channels)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -341,9 +977,9 @@ func (m *MockJWTGenerator) GenerateConnectToken(userID string) (string, int64, e
 }
 
 // GenerateConnectToken indicates an expected call of GenerateConnectToken.
-func (mr *MockJWTGeneratorMockRecorder) GenerateConnectToken(userID interface{}) *gomock.Call {
+func (mr *MockJWTGeneratorMockRecorder) GenerateConnectToken(userID, channels interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateConnectToken", reflect.TypeOf((*MockJWTGenerator)(nil).GenerateConnectToken), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateConnectToken", reflect.TypeOf((*MockJWTGenerator)(nil).GenerateConnectToken), userID, channels)
 }
 
 // GenerateSubscribeToken mocks base method.
@@ -1 +1,15 @@
+//go:generate mockgen -destination=mock_contract_test.go -package=${GOPACKAGE} -source=contract.go
 package service
+
+import (
+	"context"
+
+	"github.com/s21platform/chat-service/internal/model"
+)
+
+type DBRepo interface {
+	GetOrCreateCommentStream(ctx context.Context, entityType, entityID string) (string, error)
+	GetStreamType(ctx context.Context, streamID string) (string, error)
+	GetStreamRecentMessages(ctx context.Context, streamID, userID string, cursor *model.MessageCursor, direction string, limit int32) (*model.MessageList, error)
+	CountStreamMessages(ctx context.Context, streamID string) (int64, error)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/s21platform/chat-service/internal/model"
+)
+
+// MockDBRepo is a mock of DBRepo interface.
+type MockDBRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDBRepoMockRecorder
+}
+
+// MockDBRepoMockRecorder is the mock recorder for MockDBRepo.
+type MockDBRepoMockRecorder struct {
+	mock *MockDBRepo
+}
+
+// NewMockDBRepo creates a new mock instance.
+func NewMockDBRepo(ctrl *gomock.Controller) *MockDBRepo {
+	mock := &MockDBRepo{ctrl: ctrl}
+	mock.recorder = &MockDBRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDBRepo) EXPECT() *MockDBRepoMockRecorder {
+	return m.recorder
+}
+
+// CountStreamMessages mocks base method.
+func (m *MockDBRepo) CountStreamMessages(ctx context.Context, streamID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountStreamMessages", ctx, streamID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStreamMessages indicates an expected call of CountStreamMessages.
+func (mr *MockDBRepoMockRecorder) CountStreamMessages(ctx, streamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStreamMessages", reflect.TypeOf((*MockDBRepo)(nil).CountStreamMessages), ctx, streamID)
+}
+
+// GetOrCreateCommentStream mocks base method.
+func (m *MockDBRepo) GetOrCreateCommentStream(ctx context.Context, entityType, entityID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreateCommentStream", ctx, entityType, entityID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreateCommentStream indicates an expected call of GetOrCreateCommentStream.
+func (mr *MockDBRepoMockRecorder) GetOrCreateCommentStream(ctx, entityType, entityID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateCommentStream", reflect.TypeOf((*MockDBRepo)(nil).GetOrCreateCommentStream), ctx, entityType, entityID)
+}
+
+// GetStreamRecentMessages mocks base method.
+func (m *MockDBRepo) GetStreamRecentMessages(ctx context.Context, streamID, userID string, cursor *model.MessageCursor, direction string, limit int32) (*model.MessageList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamRecentMessages", ctx, streamID, userID, cursor, direction, limit)
+	ret0, _ := ret[0].(*model.MessageList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamRecentMessages indicates an expected call of GetStreamRecentMessages.
+func (mr *MockDBRepoMockRecorder) GetStreamRecentMessages(ctx, streamID, userID, cursor, direction, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamRecentMessages", reflect.TypeOf((*MockDBRepo)(nil).GetStreamRecentMessages), ctx, streamID, userID, cursor, direction, limit)
+}
+
+// GetStreamType mocks base method.
+func (m *MockDBRepo) GetStreamType(ctx context.Context, streamID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamType", ctx, streamID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamType indicates an expected call of GetStreamType.
+func (mr *MockDBRepoMockRecorder) GetStreamType(ctx, streamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamType", reflect.TypeOf((*MockDBRepo)(nil).GetStreamType), ctx, streamID)
+}
@@ -1,13 +1,162 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	logger_lib "github.com/s21platform/logger-lib"
+
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/model"
 	"github.com/s21platform/chat-service/pkg/chat"
 )
 
+const defaultCommentsLimit = 20
+
 type Server struct {
 	chat.UnimplementedChatServiceServer
+	repository DBRepo
 }
 
-func New() *Server {
-	return &Server{}
+func New(repo DBRepo) *Server {
+	return &Server{
+		repository: repo,
+	}
+}
+
+func (s *Server) GetOrCreateCommentStream(ctx context.Context, in *chat.GetOrCreateCommentStreamIn) (*chat.GetOrCreateCommentStreamOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetOrCreateCommentStream")
+
+	if strings.TrimSpace(in.EntityType) == "" || strings.TrimSpace(in.EntityId) == "" {
+		logger.Error("entity type and entity id are required")
+		return nil, status.Error(codes.InvalidArgument, "entity type and entity id are required")
+	}
+
+	streamID, err := s.repository.GetOrCreateCommentStream(ctx, in.EntityType, in.EntityId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to get or create comment stream: %v", err))
+		return nil, status.Errorf(codes.Internal, "failed to get or create comment stream: %v", err)
+	}
+
+	return &chat.GetOrCreateCommentStreamOut{
+		StreamId: streamID,
+	}, nil
+}
+
+func (s *Server) GetComments(ctx context.Context, in *chat.GetCommentsIn) (*chat.GetCommentsOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetComments")
+
+	if err := s.checkCommentStream(ctx, in.StreamId); err != nil {
+		logger.Error(fmt.Sprintf("failed to check comment stream: %v", err))
+		return nil, err
+	}
+
+	var cursor *model.MessageCursor
+	switch {
+	case in.Cursor != "":
+		parsed, err := model.ParseMessageCursor(in.Cursor)
+		if err != nil {
+			logger.Error(fmt.Sprintf("invalid cursor: %v", err))
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+		}
+		cursor = &parsed
+	case in.Offset != "": //nolint:staticcheck // поддержка старых клиентов
+		parsed, err := model.CursorFromTimestamp(in.Offset) //nolint:staticcheck // .
+		if err != nil {
+			logger.Error(fmt.Sprintf("invalid offset: %v", err))
+			return nil, status.Errorf(codes.InvalidArgument, "invalid offset: %v", err)
+		}
+		cursor = &parsed
+	}
+
+	limit := in.Limit
+	if limit <= 0 {
+		limit = defaultCommentsLimit
+	}
+
+	messages, err := s.repository.GetStreamRecentMessages(ctx, in.StreamId, "", cursor, model.BeforeCursorDirection, limit+1)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to fetch comments: %v", err))
+		return nil, status.Errorf(codes.Internal, "failed to fetch comments: %v", err)
+	}
+
+	var nextCursor string
+	if len(*messages) > int(limit) {
+		*messages = (*messages)[:limit]
+		nextCursor = model.NewMessageCursor((*messages)[limit-1]).Encode()
+	}
+
+	comments := make([]*chat.Comment, len(*messages))
+	for i, msg := range *messages {
+		comment := &chat.Comment{
+			Id:       msg.ID.String(),
+			SenderId: msg.SenderID.String(),
+			Content:  msg.Content,
+			SentAt:   msg.SentAt.Format(time.RFC3339),
+		}
+		if msg.RootID != nil {
+			comment.RootId = msg.RootID.String()
+		}
+		if msg.ParentID != nil {
+			comment.ParentId = msg.ParentID.String()
+		}
+		if msg.UpdatedAt != nil {
+			comment.UpdatedAt = msg.UpdatedAt.Format(time.RFC3339)
+		}
+		comments[i] = comment
+	}
+
+	return &chat.GetCommentsOut{
+		Comments:   comments,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (s *Server) GetCommentsCount(ctx context.Context, in *chat.GetCommentsCountIn) (*chat.GetCommentsCountOut, error) {
+	logger := logger_lib.FromContext(ctx, config.KeyLogger)
+	logger.AddFuncName("GetCommentsCount")
+
+	if err := s.checkCommentStream(ctx, in.StreamId); err != nil {
+		logger.Error(fmt.Sprintf("failed to check comment stream: %v", err))
+		return nil, err
+	}
+
+	count, err := s.repository.CountStreamMessages(ctx, in.StreamId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to count comments: %v", err))
+		return nil, status.Errorf(codes.Internal, "failed to count comments: %v", err)
+	}
+
+	return &chat.GetCommentsCountOut{
+		Count: count,
+	}, nil
+}
+
+func (s *Server) checkCommentStream(ctx context.Context, streamID string) error {
+	if _, err := uuid.Parse(streamID); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid stream id: %v", err)
+	}
+
+	streamType, err := s.repository.GetStreamType(ctx, streamID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get stream type: %v", err)
+	}
+
+	if streamType == "" {
+		return status.Error(codes.NotFound, "stream not found")
+	}
+
+	if streamType != model.CommentStreamType {
+		return status.Error(codes.InvalidArgument, "stream is not a comment stream")
+	}
+
+	return nil
 }
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	logger_lib "github.com/s21platform/logger-lib"
+
+	"github.com/s21platform/chat-service/internal/config"
+	"github.com/s21platform/chat-service/internal/model"
+	"github.com/s21platform/chat-service/pkg/chat"
+)
+
+func TestServer_GetOrCreateCommentStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		streamID := uuid.New().String()
+
+		mockLogger.EXPECT().AddFuncName("GetOrCreateCommentStream")
+		mockRepo.EXPECT().GetOrCreateCommentStream(gomock.Any(), "post", "42").Return(streamID, nil)
+
+		ctx := context.WithValue(context.Background(), config.KeyLogger, mockLogger)
+
+		s := New(mockRepo)
+		out, err := s.GetOrCreateCommentStream(ctx, &chat.GetOrCreateCommentStreamIn{EntityType: "post", EntityId: "42"})
+		require.NoError(t, err)
+		assert.Equal(t, streamID, out.StreamId)
+	})
+
+	t.Run("empty_entity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		mockLogger.EXPECT().AddFuncName("GetOrCreateCommentStream")
+		mockLogger.EXPECT().Error(gomock.Any())
+
+		ctx := context.WithValue(context.Background(), config.KeyLogger, mockLogger)
+
+		s := New(mockRepo)
+		_, err := s.GetOrCreateCommentStream(ctx, &chat.GetOrCreateCommentStreamIn{EntityType: "post"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_GetComments(t *testing.T) {
+	t.Parallel()
+
+	streamID := uuid.New().String()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		parentID := uuid.New()
+
+		mockLogger.EXPECT().AddFuncName("GetComments")
+		mockRepo.EXPECT().GetStreamType(gomock.Any(), streamID).Return(model.CommentStreamType, nil)
+		mockRepo.EXPECT().GetStreamRecentMessages(gomock.Any(), streamID, "", nil, model.BeforeCursorDirection, int32(defaultCommentsLimit+1)).Return(&model.MessageList{
+			{
+				ID:       uuid.New(),
+				StreamID: uuid.MustParse(streamID),
+				SenderID: uuid.New(),
+				Type:     model.TextMessageType,
+				Content:  "nice post",
+				ParentID: &parentID,
+				SentAt:   time.Now(),
+			},
+		}, nil)
+
+		ctx := context.WithValue(context.Background(), config.KeyLogger, mockLogger)
+
+		s := New(mockRepo)
+		out, err := s.GetComments(ctx, &chat.GetCommentsIn{StreamId: streamID})
+		require.NoError(t, err)
+		require.Len(t, out.Comments, 1)
+		assert.Equal(t, "nice post", out.Comments[0].Content)
+		assert.Equal(t, parentID.String(), out.Comments[0].ParentId)
+		assert.Empty(t, out.NextCursor)
+	})
+
+	t.Run("not_comment_stream", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := NewMockDBRepo(ctrl)
+		mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+		mockLogger.EXPECT().AddFuncName("GetComments")
+		mockLogger.EXPECT().Error(gomock.Any())
+		mockRepo.EXPECT().GetStreamType(gomock.Any(), streamID).Return(model.PrivateStreamType, nil)
+
+		ctx := context.WithValue(context.Background(), config.KeyLogger, mockLogger)
+
+		s := New(mockRepo)
+		_, err := s.GetComments(ctx, &chat.GetCommentsIn{StreamId: streamID})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_GetCommentsCount(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockDBRepo(ctrl)
+	mockLogger := logger_lib.NewMockLoggerInterface(ctrl)
+
+	streamID := uuid.New().String()
+
+	mockLogger.EXPECT().AddFuncName("GetCommentsCount")
+	mockRepo.EXPECT().GetStreamType(gomock.Any(), streamID).Return(model.CommentStreamType, nil)
+	mockRepo.EXPECT().CountStreamMessages(gomock.Any(), streamID).Return(int64(7), nil)
+
+	ctx := context.WithValue(context.Background(), config.KeyLogger, mockLogger)
+
+	s := New(mockRepo)
+	out, err := s.GetCommentsCount(ctx, &chat.GetCommentsCountIn{StreamId: streamID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), out.Count)
+}
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TYPE member_role AS ENUM ('owner', 'admin', 'member');
+ALTER TABLE stream_members
+    ADD COLUMN role member_role NOT NULL DEFAULT 'member';
+UPDATE stream_members sm
+SET role = 'owner'
+FROM streams s
+WHERE s.id = sm.stream_id
+  AND s.created_by = sm.user_id;
+
+-- +goose Down
+ALTER TABLE stream_members
+    DROP COLUMN IF EXISTS role;
+DROP TYPE IF EXISTS member_role;
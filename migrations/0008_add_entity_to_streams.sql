@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE streams
+    ADD COLUMN entity_type TEXT,
+    ADD COLUMN entity_id   TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_comment_stream_entity
+    ON streams (entity_type, entity_id)
+    WHERE type = 'comment';
+
+-- +goose Down
+DROP INDEX IF EXISTS unique_comment_stream_entity;
+ALTER TABLE streams
+    DROP COLUMN IF EXISTS entity_type,
+    DROP COLUMN IF EXISTS entity_id;
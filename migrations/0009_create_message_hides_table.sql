@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS message_hides
+(
+    message_id UUID NOT NULL,
+    user_id    UUID NOT NULL,
+    hidden_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, user_id),
+    FOREIGN KEY (message_id) REFERENCES messages (id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS message_hides;
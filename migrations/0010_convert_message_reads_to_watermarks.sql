@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE message_reads
+    ADD COLUMN stream_id UUID NOT NULL,
+    ADD FOREIGN KEY (stream_id) REFERENCES streams (id),
+    DROP CONSTRAINT unique_message_user_read,
+    ADD CONSTRAINT unique_stream_user_read UNIQUE (stream_id, user_id);
+
+-- +goose Down
+ALTER TABLE message_reads
+    DROP CONSTRAINT unique_stream_user_read,
+    DROP COLUMN stream_id,
+    ADD CONSTRAINT unique_message_user_read UNIQUE (message_id, user_id);
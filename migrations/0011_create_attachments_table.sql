@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS attachments
+(
+    storage_key   TEXT PRIMARY KEY,
+    uploader_id   UUID   NOT NULL,
+    mime_type     TEXT   NOT NULL,
+    size          BIGINT NOT NULL,
+    width         INT,
+    height        INT,
+    thumbnail_key TEXT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (uploader_id) REFERENCES users (id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    channel         TEXT      NOT NULL,
+    payload         JSONB     NOT NULL,
+    attempts        INT       NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_next_attempt_at ON outbox (next_attempt_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_next_attempt_at;
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,8 @@
+-- +goose Up
+INSERT INTO user_subscriptions (user_id, channel)
+SELECT id, 'personal:#' || id
+FROM users
+ON CONFLICT (user_id, channel) DO NOTHING;
+
+-- +goose Down
+DELETE FROM user_subscriptions WHERE channel LIKE 'personal:#%';
@@ -0,0 +1,9 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE member_role ADD VALUE IF NOT EXISTS 'read_only';
+
+-- +goose Down
+-- значение из enum удалить нельзя, поэтому read_only-участники возвращаются к обычной роли
+UPDATE stream_members
+SET role = 'member'
+WHERE role = 'read_only';
@@ -0,0 +1,39 @@
+-- +goose Up
+ALTER TABLE streams
+    ADD COLUMN private_pair TEXT;
+
+-- канонический ключ пары: UUID в каноническом виде, меньший первым; из уже созданных дублей ключ получает самый ранний стрим
+UPDATE streams s
+SET private_pair = p.pair
+FROM (SELECT pairs.stream_id,
+             pairs.pair,
+             ROW_NUMBER() OVER (PARTITION BY pairs.pair ORDER BY st.created_at, st.id) AS rn
+      FROM (SELECT sm.stream_id,
+                   (ARRAY_AGG(sm.user_id ORDER BY sm.user_id))[1]::text || ':' ||
+                   (ARRAY_AGG(sm.user_id ORDER BY sm.user_id))[2]::text AS pair
+            FROM stream_members sm
+            GROUP BY sm.stream_id
+            HAVING COUNT(*) = 2) pairs
+               JOIN streams st ON st.id = pairs.stream_id
+      WHERE st.type = 'private') p
+WHERE s.id = p.stream_id
+  AND p.rn = 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_private_pair
+    ON streams (private_pair)
+    WHERE type = 'private';
+
+-- ключ в другом регистре или записи UUID не обойдёт уникальность пары
+ALTER TABLE streams
+    ADD CONSTRAINT check_private_pair_canonical CHECK (
+        private_pair IS NULL OR private_pair =
+            LEAST(split_part(private_pair, ':', 1)::uuid, split_part(private_pair, ':', 2)::uuid)::text || ':' ||
+            GREATEST(split_part(private_pair, ':', 1)::uuid, split_part(private_pair, ':', 2)::uuid)::text
+        );
+
+-- +goose Down
+ALTER TABLE streams
+    DROP CONSTRAINT IF EXISTS check_private_pair_canonical;
+DROP INDEX IF EXISTS unique_private_pair;
+ALTER TABLE streams
+    DROP COLUMN IF EXISTS private_pair;
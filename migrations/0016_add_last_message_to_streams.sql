@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE streams
+    ADD COLUMN last_message_id        UUID,
+    ADD COLUMN last_message_content   TEXT,
+    ADD COLUMN last_message_sender_id UUID,
+    ADD COLUMN last_message_at        TIMESTAMP;
+
+UPDATE streams s
+SET last_message_id        = m.id,
+    last_message_content   = m.content,
+    last_message_sender_id = m.sender_id,
+    last_message_at        = m.sent_at
+FROM (SELECT DISTINCT ON (stream_id) id, stream_id, content, sender_id, sent_at
+      FROM messages
+      WHERE deleted_at IS NULL
+      ORDER BY stream_id, sent_at DESC, id DESC) m
+WHERE s.id = m.stream_id;
+
+CREATE INDEX IF NOT EXISTS idx_stream_members_active_user
+    ON stream_members (user_id)
+    WHERE left_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_stream_members_active_user;
+ALTER TABLE streams
+    DROP COLUMN IF EXISTS last_message_at,
+    DROP COLUMN IF EXISTS last_message_sender_id,
+    DROP COLUMN IF EXISTS last_message_content,
+    DROP COLUMN IF EXISTS last_message_id;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TYPE notify_level AS ENUM ('all', 'mentions', 'none');
+
+-- notify = FALSE означает, что стрим заглушён: навсегда или до muted_until
+UPDATE stream_members
+SET notify = TRUE
+WHERE notify IS NULL;
+
+ALTER TABLE stream_members
+    ALTER COLUMN notify SET NOT NULL,
+    ADD COLUMN notify_level notify_level NOT NULL DEFAULT 'all',
+    ADD COLUMN muted_until  TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE stream_members
+    DROP COLUMN IF EXISTS muted_until,
+    DROP COLUMN IF EXISTS notify_level,
+    ALTER COLUMN notify DROP NOT NULL;
+DROP TYPE IF EXISTS notify_level;
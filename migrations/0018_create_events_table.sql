@@ -0,0 +1,19 @@
+-- +goose Up
+-- журнал событий, которые уходят через outbox; в отличие от outbox записи не удаляются после доставки,
+-- поэтому клиенты SSE могут продолжить поток после переподключения. События упорядочены по (xid, id):
+-- id раздаётся при вставке, а не при коммите, и сам по себе не задаёт порядок видимости
+CREATE TABLE IF NOT EXISTS events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    xid        XID8        NOT NULL DEFAULT pg_current_xact_id(),
+    channel    TEXT        NOT NULL,
+    payload    JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_events_xid_id ON events (xid, id);
+CREATE INDEX IF NOT EXISTS idx_events_created_at ON events (created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_created_at;
+DROP INDEX IF EXISTS idx_events_xid_id;
+DROP TABLE IF EXISTS events;
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
+	sync "sync"
 	unsafe "unsafe"
 )
 
@@ -20,21 +21,484 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type GetOrCreateCommentStreamIn struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Тип сущности, например post или project
+	EntityType string `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	// Идентификатор сущности
+	EntityId      string `protobuf:"bytes,2,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrCreateCommentStreamIn) Reset() {
+	*x = GetOrCreateCommentStreamIn{}
+	mi := &file_api_chat_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrCreateCommentStreamIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrCreateCommentStreamIn) ProtoMessage() {}
+
+func (x *GetOrCreateCommentStreamIn) ProtoReflect() protoreflect.Message {
+	mi := &file_api_chat_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrCreateCommentStreamIn.ProtoReflect.Descriptor instead.
+func (*GetOrCreateCommentStreamIn) Descriptor() ([]byte, []int) {
+	return file_api_chat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetOrCreateCommentStreamIn) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *GetOrCreateCommentStreamIn) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+type GetOrCreateCommentStreamOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StreamId      string                 `protobuf:"bytes,1,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrCreateCommentStreamOut) Reset() {
+	*x = GetOrCreateCommentStreamOut{}
+	mi := &file_api_chat_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrCreateCommentStreamOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrCreateCommentStreamOut) ProtoMessage() {}
+
+func (x *GetOrCreateCommentStreamOut) ProtoReflect() protoreflect.Message {
+	mi := &file_api_chat_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrCreateCommentStreamOut.ProtoReflect.Descriptor instead.
+func (*GetOrCreateCommentStreamOut) Descriptor() ([]byte, []int) {
+	return file_api_chat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetOrCreateCommentStreamOut) GetStreamId() string {
+	if x != nil {
+		return x.StreamId
+	}
+	return ""
+}
+
+type GetCommentsIn struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	StreamId string                 `protobuf:"bytes,1,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"`
+	// Временная метка в формате RFC3339, начиная с которой отдаются комментарии. Устарело, используйте cursor
+	//
+	// Deprecated: Marked as deprecated in api/chat.proto.
+	Offset string `protobuf:"bytes,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit  int32  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Курсор из next_cursor предыдущего ответа
+	Cursor        string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsIn) Reset() {
+	*x = GetCommentsIn{}
+	mi := &file_api_chat_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsIn) ProtoMessage() {}
+
+func (x *GetCommentsIn) ProtoReflect() protoreflect.Message {
+	mi := &file_api_chat_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsIn.ProtoReflect.Descriptor instead.
+func (*GetCommentsIn) Descriptor() ([]byte, []int) {
+	return file_api_chat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCommentsIn) GetStreamId() string {
+	if x != nil {
+		return x.StreamId
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in api/chat.proto.
+func (x *GetCommentsIn) GetOffset() string {
+	if x != nil {
+		return x.Offset
+	}
+	return ""
+}
+
+func (x *GetCommentsIn) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetCommentsIn) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type Comment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SenderId      string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	RootId        string                 `protobuf:"bytes,4,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,5,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	SentAt        string                 `protobuf:"bytes,6,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_api_chat_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_api_chat_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_api_chat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *Comment) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Comment) GetRootId() string {
+	if x != nil {
+		return x.RootId
+	}
+	return ""
+}
+
+func (x *Comment) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Comment) GetSentAt() string {
+	if x != nil {
+		return x.SentAt
+	}
+	return ""
+}
+
+func (x *Comment) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type GetCommentsOut struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Comments []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	// Курсор следующей страницы, пустой если комментариев больше нет
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsOut) Reset() {
+	*x = GetCommentsOut{}
+	mi := &file_api_chat_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsOut) ProtoMessage() {}
+
+func (x *GetCommentsOut) ProtoReflect() protoreflect.Message {
+	mi := &file_api_chat_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsOut.ProtoReflect.Descriptor instead.
+func (*GetCommentsOut) Descriptor() ([]byte, []int) {
+	return file_api_chat_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCommentsOut) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *GetCommentsOut) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type GetCommentsCountIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StreamId      string                 `protobuf:"bytes,1,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsCountIn) Reset() {
+	*x = GetCommentsCountIn{}
+	mi := &file_api_chat_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsCountIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsCountIn) ProtoMessage() {}
+
+func (x *GetCommentsCountIn) ProtoReflect() protoreflect.Message {
+	mi := &file_api_chat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsCountIn.ProtoReflect.Descriptor instead.
+func (*GetCommentsCountIn) Descriptor() ([]byte, []int) {
+	return file_api_chat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetCommentsCountIn) GetStreamId() string {
+	if x != nil {
+		return x.StreamId
+	}
+	return ""
+}
+
+type GetCommentsCountOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentsCountOut) Reset() {
+	*x = GetCommentsCountOut{}
+	mi := &file_api_chat_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentsCountOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsCountOut) ProtoMessage() {}
+
+func (x *GetCommentsCountOut) ProtoReflect() protoreflect.Message {
+	mi := &file_api_chat_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsCountOut.ProtoReflect.Descriptor instead.
+func (*GetCommentsCountOut) Descriptor() ([]byte, []int) {
+	return file_api_chat_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetCommentsCountOut) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
 var File_api_chat_proto protoreflect.FileDescriptor
 
 const file_api_chat_proto_rawDesc = "" +
 	"\n" +
-	"\x0eapi/chat.proto2\r\n" +
-	"\vChatServiceB\n" +
+	"\x0eapi/chat.proto\"Z\n" +
+	"\x1aGetOrCreateCommentStreamIn\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x02 \x01(\tR\bentityId\":\n" +
+	"\x1bGetOrCreateCommentStreamOut\x12\x1b\n" +
+	"\tstream_id\x18\x01 \x01(\tR\bstreamId\"v\n" +
+	"\rGetCommentsIn\x12\x1b\n" +
+	"\tstream_id\x18\x01 \x01(\tR\bstreamId\x12\x1a\n" +
+	"\x06offset\x18\x02 \x01(\tB\x02\x18\x01R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\"\xbe\x01\n" +
+	"\aComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tsender_id\x18\x02 \x01(\tR\bsenderId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x17\n" +
+	"\aroot_id\x18\x04 \x01(\tR\x06rootId\x12\x1b\n" +
+	"\tparent_id\x18\x05 \x01(\tR\bparentId\x12\x17\n" +
+	"\asent_at\x18\x06 \x01(\tR\x06sentAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\"W\n" +
+	"\x0eGetCommentsOut\x12$\n" +
+	"\bcomments\x18\x01 \x03(\v2\b.CommentR\bcomments\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"1\n" +
+	"\x12GetCommentsCountIn\x12\x1b\n" +
+	"\tstream_id\x18\x01 \x01(\tR\bstreamId\"+\n" +
+	"\x13GetCommentsCountOut\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count2\xd9\x01\n" +
+	"\vChatService\x12W\n" +
+	"\x18GetOrCreateCommentStream\x12\x1b.GetOrCreateCommentStreamIn\x1a\x1c.GetOrCreateCommentStreamOut\"\x00\x120\n" +
+	"\vGetComments\x12\x0e.GetCommentsIn\x1a\x0f.GetCommentsOut\"\x00\x12?\n" +
+	"\x10GetCommentsCount\x12\x13.GetCommentsCountIn\x1a\x14.GetCommentsCountOut\"\x00B\n" +
 	"Z\bpkg/chatb\x06proto3"
 
-var file_api_chat_proto_goTypes = []any{}
+var (
+	file_api_chat_proto_rawDescOnce sync.Once
+	file_api_chat_proto_rawDescData []byte
+)
+
+func file_api_chat_proto_rawDescGZIP() []byte {
+	file_api_chat_proto_rawDescOnce.Do(func() {
+		file_api_chat_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_chat_proto_rawDesc), len(file_api_chat_proto_rawDesc)))
+	})
+	return file_api_chat_proto_rawDescData
+}
+
+var file_api_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_api_chat_proto_goTypes = []any{
+	(*GetOrCreateCommentStreamIn)(nil),  // 0: GetOrCreateCommentStreamIn
+	(*GetOrCreateCommentStreamOut)(nil), // 1: GetOrCreateCommentStreamOut
+	(*GetCommentsIn)(nil),               // 2: GetCommentsIn
+	(*Comment)(nil),                     // 3: Comment
+	(*GetCommentsOut)(nil),              // 4: GetCommentsOut
+	(*GetCommentsCountIn)(nil),          // 5: GetCommentsCountIn
+	(*GetCommentsCountOut)(nil),         // 6: GetCommentsCountOut
+}
 var file_api_chat_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3, // 0: GetCommentsOut.comments:type_name -> Comment
+	0, // 1: ChatService.GetOrCreateCommentStream:input_type -> GetOrCreateCommentStreamIn
+	2, // 2: ChatService.GetComments:input_type -> GetCommentsIn
+	5, // 3: ChatService.GetCommentsCount:input_type -> GetCommentsCountIn
+	1, // 4: ChatService.GetOrCreateCommentStream:output_type -> GetOrCreateCommentStreamOut
+	4, // 5: ChatService.GetComments:output_type -> GetCommentsOut
+	6, // 6: ChatService.GetCommentsCount:output_type -> GetCommentsCountOut
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_api_chat_proto_init() }
@@ -48,12 +512,13 @@ func file_api_chat_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_chat_proto_rawDesc), len(file_api_chat_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   0,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_chat_proto_goTypes,
 		DependencyIndexes: file_api_chat_proto_depIdxs,
+		MessageInfos:      file_api_chat_proto_msgTypes,
 	}.Build()
 	File_api_chat_proto = out.File
 	file_api_chat_proto_goTypes = nil
@@ -7,7 +7,10 @@
 package chat
 
 import (
+	context "context"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -15,10 +18,22 @@ import (
 // Requires gRPC-Go v1.64.0 or later.
 const _ = grpc.SupportPackageIsVersion9
 
+const (
+	ChatService_GetOrCreateCommentStream_FullMethodName = "/ChatService/GetOrCreateCommentStream"
+	ChatService_GetComments_FullMethodName              = "/ChatService/GetComments"
+	ChatService_GetCommentsCount_FullMethodName         = "/ChatService/GetCommentsCount"
+)
+
 // ChatServiceClient is the client API for ChatService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ChatServiceClient interface {
+	// Возвращает стрим комментариев для внешней сущности, создавая его при первом обращении
+	GetOrCreateCommentStream(ctx context.Context, in *GetOrCreateCommentStreamIn, opts ...grpc.CallOption) (*GetOrCreateCommentStreamOut, error)
+	// Возвращает комментарии стрима, начиная с самых новых
+	GetComments(ctx context.Context, in *GetCommentsIn, opts ...grpc.CallOption) (*GetCommentsOut, error)
+	// Возвращает количество комментариев в стриме
+	GetCommentsCount(ctx context.Context, in *GetCommentsCountIn, opts ...grpc.CallOption) (*GetCommentsCountOut, error)
 }
 
 type chatServiceClient struct {
@@ -29,10 +44,46 @@ func NewChatServiceClient(cc grpc.ClientConnInterface) ChatServiceClient {
 	return &chatServiceClient{cc}
 }
 
+func (c *chatServiceClient) GetOrCreateCommentStream(ctx context.Context, in *GetOrCreateCommentStreamIn, opts ...grpc.CallOption) (*GetOrCreateCommentStreamOut, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrCreateCommentStreamOut)
+	err := c.cc.Invoke(ctx, ChatService_GetOrCreateCommentStream_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetComments(ctx context.Context, in *GetCommentsIn, opts ...grpc.CallOption) (*GetCommentsOut, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCommentsOut)
+	err := c.cc.Invoke(ctx, ChatService_GetComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetCommentsCount(ctx context.Context, in *GetCommentsCountIn, opts ...grpc.CallOption) (*GetCommentsCountOut, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCommentsCountOut)
+	err := c.cc.Invoke(ctx, ChatService_GetCommentsCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatServiceServer is the server API for ChatService service.
 // All implementations must embed UnimplementedChatServiceServer
 // for forward compatibility.
 type ChatServiceServer interface {
+	// Возвращает стрим комментариев для внешней сущности, создавая его при первом обращении
+	GetOrCreateCommentStream(context.Context, *GetOrCreateCommentStreamIn) (*GetOrCreateCommentStreamOut, error)
+	// Возвращает комментарии стрима, начиная с самых новых
+	GetComments(context.Context, *GetCommentsIn) (*GetCommentsOut, error)
+	// Возвращает количество комментариев в стриме
+	GetCommentsCount(context.Context, *GetCommentsCountIn) (*GetCommentsCountOut, error)
 	mustEmbedUnimplementedChatServiceServer()
 }
 
@@ -43,6 +94,15 @@ type ChatServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedChatServiceServer struct{}
 
+func (UnimplementedChatServiceServer) GetOrCreateCommentStream(context.Context, *GetOrCreateCommentStreamIn) (*GetOrCreateCommentStreamOut, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrCreateCommentStream not implemented")
+}
+func (UnimplementedChatServiceServer) GetComments(context.Context, *GetCommentsIn) (*GetCommentsOut, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComments not implemented")
+}
+func (UnimplementedChatServiceServer) GetCommentsCount(context.Context, *GetCommentsCountIn) (*GetCommentsCountOut, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCommentsCount not implemented")
+}
 func (UnimplementedChatServiceServer) mustEmbedUnimplementedChatServiceServer() {}
 func (UnimplementedChatServiceServer) testEmbeddedByValue()                     {}
 
@@ -64,13 +124,80 @@ func RegisterChatServiceServer(s grpc.ServiceRegistrar, srv ChatServiceServer) {
 	s.RegisterService(&ChatService_ServiceDesc, srv)
 }
 
+func _ChatService_GetOrCreateCommentStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrCreateCommentStreamIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetOrCreateCommentStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetOrCreateCommentStream_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetOrCreateCommentStream(ctx, req.(*GetOrCreateCommentStreamIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommentsIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetComments(ctx, req.(*GetCommentsIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetCommentsCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommentsCountIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetCommentsCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetCommentsCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetCommentsCount(ctx, req.(*GetCommentsCountIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChatService_ServiceDesc is the grpc.ServiceDesc for ChatService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var ChatService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "ChatService",
 	HandlerType: (*ChatServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams:     []grpc.StreamDesc{},
-	Metadata:    "api/chat.proto",
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOrCreateCommentStream",
+			Handler:    _ChatService_GetOrCreateCommentStream_Handler,
+		},
+		{
+			MethodName: "GetComments",
+			Handler:    _ChatService_GetComments_Handler,
+		},
+		{
+			MethodName: "GetCommentsCount",
+			Handler:    _ChatService_GetCommentsCount_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/chat.proto",
 }